@@ -0,0 +1,76 @@
+/*
+ * detect-languages scans the translations of all nodes and reports those,
+ * that look like they were submitted under the wrong language
+ */
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"sort"
+
+	dbiface "github.com/suxatcode/learn-graph-poc-backend/db"
+	dbimpl "github.com/suxatcode/learn-graph-poc-backend/db/postgres"
+	"github.com/suxatcode/learn-graph-poc-backend/internal/langdetect"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+var (
+	PGHost     = flag.String("pg_host", "postgres", "postgres host")
+	PGPassword = flag.String("pg_password", "example", "postgres password")
+	BatchSize  = flag.Int("batch_size", 1000, "number of nodes read at once")
+)
+
+func init() {
+	flag.Parse()
+}
+
+func main() {
+	pgConfig := postgres.Config{
+		DSN: fmt.Sprintf("host=%s user=learngraph password=%s dbname=learngraph port=5432 sslmode=disable", *PGHost, *PGPassword),
+	}
+	db, err := gorm.Open(postgres.New(pgConfig), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("%v: authentication with DSN: '%v' failed", err, pgConfig.DSN)
+	}
+	scanned, suspects := 0, 0
+	nodes := []dbimpl.Node{}
+	err = db.FindInBatches(&nodes, *BatchSize, func(tx *gorm.DB, batch int) error {
+		for _, node := range nodes {
+			suspects += report(node.ID, "description", node.Description)
+			suspects += report(node.ID, "resources", node.Resources)
+		}
+		scanned += len(nodes)
+		return nil
+	}).Error
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Scanned %d nodes, found %d suspected mislabeled translations.", scanned, suspects)
+}
+
+// report prints every translation of text, that does not look like its
+// language, and returns their number.
+func report(nodeID uint, field string, text dbiface.Text) int {
+	languages := make([]string, 0, len(text))
+	for lang := range text {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	suspects := 0
+	for _, lang := range languages {
+		check := langdetect.Check(lang, text[lang])
+		if !check.Mismatch {
+			continue
+		}
+		suggested := check.Suggested
+		if suggested == "" {
+			suggested = "?"
+		}
+		fmt.Printf("node %d %s: labeled '%s', looks like '%s': %q\n", nodeID, field, lang, suggested, text[lang])
+		suspects++
+	}
+	return suspects
+}
@@ -0,0 +1,39 @@
+/*
+ * recompute-weights aggregates the weight of every edge anew from its votes,
+ * run it after changing VOTE_AGGREGATION
+ */
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	dbiface "github.com/suxatcode/learn-graph-poc-backend/db"
+	dbimpl "github.com/suxatcode/learn-graph-poc-backend/db/postgres"
+)
+
+var (
+	PGHost     = flag.String("pg_host", "postgres", "postgres host")
+	PGPassword = flag.String("pg_password", "example", "postgres password")
+	BatchSize  = flag.Int("batch_size", 1000, "number of edges recomputed per transaction")
+)
+
+func init() {
+	flag.Parse()
+}
+
+func main() {
+	// the aggregation strategy is taken from the environment, as for the server
+	conf := dbiface.GetEnvConfig()
+	conf.PGHost, conf.PGPassword = *PGHost, *PGPassword
+	pgdb, err := dbimpl.NewPostgresDB(conf)
+	if err != nil {
+		log.Fatal(err)
+	}
+	changed, err := pgdb.(*dbimpl.PostgresDB).RecomputeEdgeWeights(context.Background(), *BatchSize)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Recomputed edge weights using '%s' aggregation, %d edges changed.", conf.VoteAggregation, changed)
+}
@@ -0,0 +1,196 @@
+// Package aggregate combines the weight votes of users on an edge into the
+// weight of the edge.
+package aggregate
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Vote is the most recent vote of a single user on an edge.
+type Vote struct {
+	Weight float64
+	Time   time.Time
+	// Reputation of the voting user, at least 0, see ReputationOf.
+	Reputation float64
+}
+
+// Aggregator combines the votes on an edge into its weight. Aggregate is
+// never called without votes.
+type Aggregator interface {
+	Aggregate(votes []Vote) float64
+}
+
+const (
+	StrategyMean       = "mean"
+	StrategyMedian     = "median"
+	StrategyTrimmed    = "trimmed"
+	StrategyReputation = "reputation"
+	StrategyTimeDecay  = "timedecay"
+)
+
+// Config selects and parametrizes the aggregation strategy.
+type Config struct {
+	// one of the Strategy* constants, empty selects the mean
+	Strategy string
+	// fraction of votes dropped at each end by StrategyTrimmed
+	TrimFraction float64
+	// age after which the influence of a vote halves for StrategyTimeDecay
+	HalfLife time.Duration
+	// Now returns the current time for StrategyTimeDecay, defaults to time.Now
+	Now func() time.Time
+}
+
+// New returns the Aggregator selected by conf.
+func New(conf Config) (Aggregator, error) {
+	switch conf.Strategy {
+	case "", StrategyMean:
+		return Mean{}, nil
+	case StrategyMedian:
+		return Median{}, nil
+	case StrategyTrimmed:
+		if conf.TrimFraction < 0 || conf.TrimFraction >= 0.5 {
+			return nil, fmt.Errorf("trim fraction must be within [0,0.5), got %v", conf.TrimFraction)
+		}
+		return TrimmedMean{Fraction: conf.TrimFraction}, nil
+	case StrategyReputation:
+		return ReputationWeightedMean{}, nil
+	case StrategyTimeDecay:
+		if conf.HalfLife <= 0 {
+			return nil, fmt.Errorf("half-life must be positive, got %v", conf.HalfLife)
+		}
+		now := conf.Now
+		if now == nil {
+			now = time.Now
+		}
+		return TimeDecayedMean{HalfLife: conf.HalfLife, Now: now}, nil
+	}
+	return nil, fmt.Errorf("unknown vote aggregation strategy '%s'", conf.Strategy)
+}
+
+// Mean is the arithmetic mean of all votes.
+type Mean struct{}
+
+func (Mean) Aggregate(votes []Vote) float64 {
+	return weightedMean(votes, func(Vote) float64 { return 1 })
+}
+
+// Median is the middle vote, or the mean of the two middle votes.
+type Median struct{}
+
+func (Median) Aggregate(votes []Vote) float64 {
+	weights := sortedWeights(votes)
+	middle := len(weights) / 2
+	if len(weights)%2 == 0 {
+		return (weights[middle-1] + weights[middle]) / 2
+	}
+	return weights[middle]
+}
+
+// TrimmedMean is the mean after dropping the lowest and highest Fraction of
+// the votes, at least one vote is always kept.
+type TrimmedMean struct {
+	Fraction float64
+}
+
+func (t TrimmedMean) Aggregate(votes []Vote) float64 {
+	weights := sortedWeights(votes)
+	trim := int(math.Floor(float64(len(weights)) * t.Fraction))
+	if 2*trim >= len(weights) {
+		trim = (len(weights) - 1) / 2
+	}
+	kept := weights[trim : len(weights)-trim]
+	sum := 0.0
+	for _, weight := range kept {
+		sum += weight
+	}
+	return sum / float64(len(kept))
+}
+
+// ReputationWeightedMean weights every vote by the reputation of its user.
+type ReputationWeightedMean struct{}
+
+func (ReputationWeightedMean) Aggregate(votes []Vote) float64 {
+	return weightedMean(votes, func(vote Vote) float64 { return vote.Reputation })
+}
+
+// MaxReputation caps the reputation of a user, see ReputationOf.
+const MaxReputation = 5.0
+
+// ReputationOf returns the reputation of a user with the given number of
+// edits, growing logarithmically up to MaxReputation so that prolific users
+// cannot dominate.
+func ReputationOf(edits int) float64 {
+	return math.Min(1+math.Log1p(float64(edits)), MaxReputation)
+}
+
+// TimeDecayedMean weights every vote by 2^(-age/HalfLife).
+type TimeDecayedMean struct {
+	HalfLife time.Duration
+	Now      func() time.Time
+}
+
+func (t TimeDecayedMean) Aggregate(votes []Vote) float64 {
+	now := t.Now()
+	return weightedMean(votes, func(vote Vote) float64 {
+		age := math.Max(0, now.Sub(vote.Time).Seconds())
+		return math.Exp2(-age / t.HalfLife.Seconds())
+	})
+}
+
+// weightedMean falls back to the arithmetic mean, if all weights are zero.
+func weightedMean(votes []Vote, weightOf func(Vote) float64) float64 {
+	sum, total := 0.0, 0.0
+	for _, vote := range votes {
+		w := weightOf(vote)
+		sum += w * vote.Weight
+		total += w
+	}
+	if total == 0 {
+		return Mean{}.Aggregate(votes)
+	}
+	return sum / total
+}
+
+func sortedWeights(votes []Vote) []float64 {
+	weights := make([]float64, 0, len(votes))
+	for _, vote := range votes {
+		weights = append(weights, vote.Weight)
+	}
+	sort.Float64s(weights)
+	return weights
+}
+
+// StdDev is the population standard deviation of the votes.
+func StdDev(votes []Vote) float64 {
+	mean := Mean{}.Aggregate(votes)
+	sum := 0.0
+	for _, vote := range votes {
+		sum += (vote.Weight - mean) * (vote.Weight - mean)
+	}
+	return math.Sqrt(sum / float64(len(votes)))
+}
+
+// Bucket counts the votes with From <= Weight < To.
+type Bucket struct {
+	From, To float64
+	Count    int
+}
+
+// Histogram counts the votes in buckets of the given width, aligned at
+// multiples of width. Empty buckets are omitted, the others are ordered by
+// weight.
+func Histogram(votes []Vote, width float64) []Bucket {
+	buckets := []Bucket{}
+	for _, weight := range sortedWeights(votes) {
+		from := math.Floor(weight/width) * width
+		if len(buckets) > 0 && buckets[len(buckets)-1].From == from {
+			buckets[len(buckets)-1].Count++
+			continue
+		}
+		buckets = append(buckets, Bucket{From: from, To: from + width, Count: 1})
+	}
+	return buckets
+}
@@ -0,0 +1,123 @@
+package aggregate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testNow = time.Date(2000, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+func weights(ws ...float64) []Vote {
+	votes := make([]Vote, 0, len(ws))
+	for _, w := range ws {
+		votes = append(votes, Vote{Weight: w, Time: testNow, Reputation: 1})
+	}
+	return votes
+}
+
+func TestAggregators(t *testing.T) {
+	for _, test := range []struct {
+		Name       string
+		Aggregator Aggregator
+		Votes      []Vote
+		Exp        float64
+	}{
+		{Name: "mean", Aggregator: Mean{}, Votes: weights(2, 4, 9), Exp: 5},
+		{Name: "mean of single vote", Aggregator: Mean{}, Votes: weights(3), Exp: 3},
+		{Name: "median odd", Aggregator: Median{}, Votes: weights(9, 2, 4), Exp: 4},
+		{Name: "median even", Aggregator: Median{}, Votes: weights(10, 2, 4, 1), Exp: 3},
+		{Name: "median ignores troll vote", Aggregator: Median{}, Votes: weights(5, 6, 1, 10, 10), Exp: 6},
+		{Name: "trimmed mean", Aggregator: TrimmedMean{Fraction: 0.2}, Votes: weights(1, 5, 5, 6, 10), Exp: 16.0 / 3},
+		{Name: "trimmed mean too few votes to trim", Aggregator: TrimmedMean{Fraction: 0.2}, Votes: weights(1, 10), Exp: 5.5},
+		{Name: "trimmed mean keeps one vote", Aggregator: TrimmedMean{Fraction: 0.49}, Votes: weights(1, 4, 10), Exp: 4},
+		{
+			Name:       "reputation weighted",
+			Aggregator: ReputationWeightedMean{},
+			Votes:      []Vote{{Weight: 2, Reputation: 3}, {Weight: 10, Reputation: 1}},
+			Exp:        4,
+		},
+		{
+			Name:       "reputation weighted without reputation",
+			Aggregator: ReputationWeightedMean{},
+			Votes:      []Vote{{Weight: 2}, {Weight: 10}},
+			Exp:        6,
+		},
+		{
+			Name:       "time decayed",
+			Aggregator: TimeDecayedMean{HalfLife: time.Hour, Now: func() time.Time { return testNow }},
+			Votes:      []Vote{{Weight: 1, Time: testNow}, {Weight: 10, Time: testNow.Add(-time.Hour)}},
+			Exp:        4,
+		},
+		{
+			Name:       "time decayed vote from the future",
+			Aggregator: TimeDecayedMean{HalfLife: time.Hour, Now: func() time.Time { return testNow }},
+			Votes:      []Vote{{Weight: 1, Time: testNow}, {Weight: 7, Time: testNow.Add(time.Hour)}},
+			Exp:        4,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.InDelta(t, test.Exp, test.Aggregator.Aggregate(test.Votes), 1e-9)
+		})
+	}
+}
+
+func TestReputationOf(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(1.0, ReputationOf(0))
+	assert.Less(ReputationOf(1), ReputationOf(10))
+	assert.Less(ReputationOf(1000), 10*ReputationOf(1))
+	assert.Equal(MaxReputation, ReputationOf(1000000))
+}
+
+func TestNew(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Config   Config
+		Exp      Aggregator
+		ExpError bool
+	}{
+		{Name: "default", Config: Config{}, Exp: Mean{}},
+		{Name: "mean", Config: Config{Strategy: StrategyMean}, Exp: Mean{}},
+		{Name: "median", Config: Config{Strategy: StrategyMedian}, Exp: Median{}},
+		{Name: "trimmed", Config: Config{Strategy: StrategyTrimmed, TrimFraction: 0.1}, Exp: TrimmedMean{Fraction: 0.1}},
+		{Name: "trimmed too much", Config: Config{Strategy: StrategyTrimmed, TrimFraction: 0.5}, ExpError: true},
+		{Name: "reputation", Config: Config{Strategy: StrategyReputation}, Exp: ReputationWeightedMean{}},
+		{Name: "time decay without half-life", Config: Config{Strategy: StrategyTimeDecay}, ExpError: true},
+		{Name: "unknown", Config: Config{Strategy: "mode"}, ExpError: true},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			aggregator, err := New(test.Config)
+			if test.ExpError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.Exp, aggregator)
+		})
+	}
+	aggregator, err := New(Config{Strategy: StrategyTimeDecay, HalfLife: time.Hour})
+	assert.NoError(t, err)
+	assert.IsType(t, TimeDecayedMean{}, aggregator)
+}
+
+func TestStdDev(t *testing.T) {
+	assert := assert.New(t)
+	assert.InDelta(0, StdDev(weights(3)), 1e-9)
+	assert.InDelta(2, StdDev(weights(2, 4, 4, 4, 5, 5, 7, 9)), 1e-9)
+}
+
+func TestHistogram(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal([]Bucket{}, Histogram(nil, 1))
+	assert.Equal([]Bucket{
+		{From: 1, To: 2, Count: 2},
+		{From: 4, To: 5, Count: 1},
+		{From: 10, To: 11, Count: 1},
+	}, Histogram(weights(10, 1, 4.5, 1.9), 1))
+	assert.Equal([]Bucket{
+		{From: 0, To: 5, Count: 3},
+		{From: 5, To: 10, Count: 1},
+	}, Histogram(weights(5, 1, 4.5, 1.9), 5))
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql/driver"
 	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/caarlos0/env/v6"
 	"github.com/pkg/errors"
@@ -11,19 +14,108 @@ import (
 )
 
 type GraphDB interface {
-	Graph(ctx context.Context) (*model.Graph, error)
+	Graph(ctx context.Context, filter GraphFilter) (*model.Graph, error)
+	// Subgraph returns all nodes reachable within `depth` edges from node
+	// `center` following edges in `direction`, and all edges between them.
+	Subgraph(ctx context.Context, center string, depth int, direction model.Direction) (*model.Graph, error)
+	// GraphOfNodes returns the nodes with the given IDs and all edges between
+	// them.
+	GraphOfNodes(ctx context.Context, nodeIDs []string) (*model.Graph, error)
 	Node(ctx context.Context, ID string) (*model.Node, error)
 	// returns ID of the created node on success
-	CreateNode(ctx context.Context, user User, description *model.Text, resources *model.Text) (string, error)
+	// CreateNode returns a *DuplicateNodesError instead of creating the node,
+	// if a node with a similar description exists in any of the given
+	// languages, unless force is set.
+	CreateNode(ctx context.Context, user User, description *model.Text, resources *model.Text, force bool) (string, error)
 	// returns ID of the created edge on success
-	CreateEdge(ctx context.Context, user User, from, to string, weight float64) (string, error)
+	CreateEdge(ctx context.Context, user User, from, to string, weight float64, edgeType EdgeType) (string, error)
 	EditNode(ctx context.Context, user User, nodeID string, description *model.Text, resources *model.Text) error
+	// RevertNode restores the description and resources of a node to the
+	// state after the node edit `editID`.
+	RevertNode(ctx context.Context, user User, nodeID, editID string) error
+	// AddEdgeWeightVote records the vote and marks the edge for aggregation,
+	// its weight is updated by AggregateDirtyEdges.
 	AddEdgeWeightVote(ctx context.Context, user User, edgeID string, weight float64) error
+	// AggregateDirtyEdges aggregates the weight of at most `limit` edges, that
+	// received votes since their last aggregation, and returns their IDs.
+	// Concurrent calls never aggregate the same edges.
+	AggregateDirtyEdges(ctx context.Context, limit int) ([]string, error)
+	// RetractEdgeWeightVote withdraws the vote of user on the edge and marks
+	// the edge for aggregation.
+	RetractEdgeWeightVote(ctx context.Context, user User, edgeID string) error
+	// EdgeVoteStats describes the distribution of the most recent vote of
+	// each user on the edge.
+	EdgeVoteStats(ctx context.Context, edgeID string) (*model.EdgeVoteStats, error)
+	// EdgeVotes returns the most recent vote of user on every edge, ordered by
+	// edge ID.
+	EdgeVotes(ctx context.Context, user User) ([]*model.EdgeVote, error)
 	DeleteNode(ctx context.Context, user User, ID string) error
 	DeleteEdge(ctx context.Context, user User, ID string) error
+	// MergeNodes merges node `remove` into node `keep`, moving its edges,
+	// translations and history. Afterwards `remove` resolves to `keep`. Only
+	// admins and moderators may merge nodes.
+	MergeNodes(ctx context.Context, user User, keep, remove string) error
+	// ResolveNodeIDs returns the IDs of the nodes, that the given nodes were
+	// merged into, IDs of nodes not merged are returned unchanged.
+	ResolveNodeIDs(ctx context.Context, IDs []string) ([]string, error)
+	// ResolveEdgeIDs returns the IDs of the edges, that the given edges were
+	// folded into when merging their nodes, other IDs are returned unchanged.
+	ResolveEdgeIDs(ctx context.Context, IDs []string) ([]string, error)
+	// RemoveTranslation removes the description or resources of a node in one
+	// language, a node keeps at least one description.
+	RemoveTranslation(ctx context.Context, user User, nodeID, language string, field model.TranslationField) error
+	// MoveTranslation moves description and resources of a node from one
+	// language to another language, that the node has no text in yet.
+	MoveTranslation(ctx context.Context, user User, nodeID, fromLanguage, toLanguage string) error
+	// MoveTranslations is the admin only bulk variant of MoveTranslation, nodes
+	// with text in toLanguage are skipped. Returns the IDs of changed nodes.
+	MoveTranslations(ctx context.Context, user User, filter TranslationFilter, fromLanguage, toLanguage string) ([]string, error)
+	// RemoveTranslations is the admin only bulk variant of RemoveTranslation,
+	// nodes left without a description are skipped. Returns the IDs of
+	// changed nodes.
+	RemoveTranslations(ctx context.Context, user User, filter TranslationFilter, language string, field model.TranslationField) ([]string, error)
+	// DeletedNodes and DeletedEdges list the contents of the trash, they may
+	// only be called by admins.
+	DeletedNodes(ctx context.Context, user User) ([]*model.DeletedNode, error)
+	DeletedEdges(ctx context.Context, user User) ([]*model.DeletedEdge, error)
+	// RestoreNode and RestoreEdge move an entity out of the trash, they may
+	// only be called by admins.
+	RestoreNode(ctx context.Context, user User, ID string) error
+	RestoreEdge(ctx context.Context, user User, ID string) error
+	// PurgeTrash permanently removes all nodes and edges deleted before
+	// `olderThan`, including their edit history.
+	PurgeTrash(ctx context.Context, olderThan time.Time) error
 	NodeEdits(ctx context.Context, ID string) ([]*model.NodeEdit, error)
+	// NodeEditsByID returns the edits of node nodeID in the order of editIDs,
+	// with all translations of their description and resources.
+	NodeEditsByID(ctx context.Context, nodeID string, editIDs []string) ([]NodeEdit, error)
 	EdgeEdits(ctx context.Context, ID string) ([]*model.EdgeEdit, error)
-	NodeMatchFuzzy(ctx context.Context, substring string) ([]*model.Node, error)
+	// NodeMatchFuzzy returns nodes with a description similar to substring,
+	// restricted to nodes with any of the tags tagIDs, if not empty.
+	NodeMatchFuzzy(ctx context.Context, substring string, tagIDs []string) ([]*model.Node, error)
+	// Search returns the nodes matching the full-text query in description or
+	// resources in the given language, best matches first.
+	Search(ctx context.Context, query, language string, limit, offset int) ([]*model.SearchHit, error)
+	// TranslationCoverage counts the nodes with a description or resources
+	// per supported language.
+	TranslationCoverage(ctx context.Context) ([]*model.TranslationCoverage, error)
+	// UntranslatedNodes returns up to `first` nodes without a description in
+	// language, ordered by ID and starting after node ID `after`.
+	UntranslatedNodes(ctx context.Context, language string, first int, after string) ([]*model.Node, error)
+	Tags(ctx context.Context) ([]*model.Tag, error)
+	// returns ID of the created tag on success
+	CreateTag(ctx context.Context, user User, name *model.Text) (string, error)
+	TagNode(ctx context.Context, user User, nodeID, tagID string) error
+	UntagNode(ctx context.Context, user User, nodeID, tagID string) error
+	// NodeResources returns the resources of the given nodes by node ID,
+	// ordered by votes.
+	NodeResources(ctx context.Context, nodeIDs []string) (map[string][]*model.Resource, error)
+	ResourceEdits(ctx context.Context, resourceID string) ([]*model.ResourceEdit, error)
+	// returns ID of the created resource on success
+	CreateResource(ctx context.Context, user User, nodeID string, resource model.ResourceInput) (string, error)
+	EditResource(ctx context.Context, user User, resourceID string, resource model.ResourceInput) error
+	DeleteResource(ctx context.Context, user User, resourceID string) error
+	VoteResource(ctx context.Context, user User, resourceID string) error
 }
 
 type UserDB interface {
@@ -44,6 +136,11 @@ type DB interface {
 type Config struct {
 	PGHost     string `env:"DB_POSTGRES_HOST" envDefault:"localhost"`
 	PGPassword string `env:"DB_POSTGRES_PASSWORD" envDefault:"example"`
+	// VoteAggregation selects how the votes on an edge are combined into its
+	// weight, see package aggregate.
+	VoteAggregation  string        `env:"VOTE_AGGREGATION" envDefault:"mean"`
+	VoteTrimFraction float64       `env:"VOTE_TRIM_FRACTION" envDefault:"0.1"`
+	VoteHalfLife     time.Duration `env:"VOTE_HALF_LIFE" envDefault:"4320h"`
 }
 
 func GetEnvConfig() Config {
@@ -75,8 +172,17 @@ type NodeEdit struct {
 type NodeEditType string
 
 const (
-	NodeEditTypeCreate NodeEditType = "create"
-	NodeEditTypeEdit   NodeEditType = "edit"
+	NodeEditTypeCreate  NodeEditType = "create"
+	NodeEditTypeEdit    NodeEditType = "edit"
+	NodeEditTypeRevert  NodeEditType = "revert"
+	NodeEditTypeDelete  NodeEditType = "delete"
+	NodeEditTypeRestore NodeEditType = "restore"
+	NodeEditTypeTag     NodeEditType = "tag"
+	NodeEditTypeUntag   NodeEditType = "untag"
+	NodeEditTypeMerge   NodeEditType = "merge"
+	// a translation was removed from or moved to another language of a node
+	NodeEditTypeRemoveTranslation NodeEditType = "removeTranslation"
+	NodeEditTypeMoveTranslation   NodeEditType = "moveTranslation"
 )
 
 type EdgeEdit struct {
@@ -91,10 +197,89 @@ type EdgeEdit struct {
 type EdgeEditType string
 
 const (
-	EdgeEditTypeCreate EdgeEditType = "create"
-	EdgeEditTypeVote   EdgeEditType = "edit"
+	EdgeEditTypeCreate  EdgeEditType = "create"
+	EdgeEditTypeVote    EdgeEditType = "edit"
+	EdgeEditTypeDelete  EdgeEditType = "delete"
+	EdgeEditTypeRestore EdgeEditType = "restore"
+	// EdgeEditTypeRetract withdraws the previous vote of the user
+	EdgeEditTypeRetract EdgeEditType = "retract"
 )
 
+type ResourceKind string
+
+const (
+	ResourceKindVideo    ResourceKind = "video"
+	ResourceKindBook     ResourceKind = "book"
+	ResourceKindArticle  ResourceKind = "article"
+	ResourceKindExercise ResourceKind = "exercise"
+)
+
+type ResourceEditType string
+
+const (
+	ResourceEditTypeCreate ResourceEditType = "create"
+	ResourceEditTypeEdit   ResourceEditType = "edit"
+	ResourceEditTypeDelete ResourceEditType = "delete"
+)
+
+// EdgeType describes how the source of an edge relates to its target.
+type EdgeType string
+
+const (
+	// the source must be understood before the target, only these edges are
+	// checked for cycles
+	EdgeTypePrerequisite EdgeType = "prerequisite"
+	EdgeTypeRelated      EdgeType = "related"
+	EdgeTypePartOf       EdgeType = "partOf"
+	EdgeTypeExampleOf    EdgeType = "exampleOf"
+)
+
+// GraphFilter restricts the graph returned by GraphDB.Graph, the zero value
+// returns the whole graph.
+type GraphFilter struct {
+	// EdgeTypes restricts the edges to the given types, if not empty.
+	EdgeTypes []EdgeType
+	// TagIDs restricts the nodes to those with any of the given tags, and the
+	// edges to those between these nodes, if not empty.
+	TagIDs []string
+}
+
+// TranslationFilter selects the nodes of a bulk translation change, empty
+// fields do not restrict the selection.
+type TranslationFilter struct {
+	NodeIDs []string
+	// ID of a user who edited the node
+	EditedBy string
+	// POSIX regular expression the description in the source language must
+	// match
+	Matches string
+}
+
+// CycleError is returned when creating an edge would introduce a cycle into
+// the graph. Cycle lists the node IDs along the cycle, the first and last
+// entry being the same node.
+type CycleError struct {
+	Cycle []string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("edge would create a cycle: %s", strings.Join(e.Cycle, " -> "))
+}
+
+// DuplicateNodesError is returned when a node to be created has a description
+// similar to the description of existing nodes.
+type DuplicateNodesError struct {
+	Duplicates []*model.Node
+}
+
+func (e *DuplicateNodesError) Error() string {
+	ids := []string{}
+	for _, node := range e.Duplicates {
+		ids = append(ids, node.ID)
+	}
+	return fmt.Sprintf("similar nodes already exist: %s", strings.Join(ids, ", "))
+}
+
 type Edge struct {
 	Document
 	From   string  `json:"_from"`
@@ -114,7 +299,8 @@ type User struct {
 type RoleType string
 
 const (
-	RoleAdmin RoleType = "admin"
+	RoleAdmin     RoleType = "admin"
+	RoleModerator RoleType = "moderator"
 )
 
 type AuthenticationToken struct {
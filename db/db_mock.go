@@ -7,6 +7,7 @@ package db
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/suxatcode/learn-graph-poc-backend/graph/model"
@@ -49,34 +50,79 @@ func (mr *MockDBMockRecorder) AddEdgeWeightVote(arg0, arg1, arg2, arg3 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEdgeWeightVote", reflect.TypeOf((*MockDB)(nil).AddEdgeWeightVote), arg0, arg1, arg2, arg3)
 }
 
+// AggregateDirtyEdges mocks base method.
+func (m *MockDB) AggregateDirtyEdges(arg0 context.Context, arg1 int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AggregateDirtyEdges", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AggregateDirtyEdges indicates an expected call of AggregateDirtyEdges.
+func (mr *MockDBMockRecorder) AggregateDirtyEdges(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregateDirtyEdges", reflect.TypeOf((*MockDB)(nil).AggregateDirtyEdges), arg0, arg1)
+}
+
 // CreateEdge mocks base method.
-func (m *MockDB) CreateEdge(arg0 context.Context, arg1 User, arg2, arg3 string, arg4 float64) (string, error) {
+func (m *MockDB) CreateEdge(arg0 context.Context, arg1 User, arg2, arg3 string, arg4 float64, arg5 EdgeType) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateEdge", arg0, arg1, arg2, arg3, arg4)
+	ret := m.ctrl.Call(m, "CreateEdge", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateEdge indicates an expected call of CreateEdge.
-func (mr *MockDBMockRecorder) CreateEdge(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+func (mr *MockDBMockRecorder) CreateEdge(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEdge", reflect.TypeOf((*MockDB)(nil).CreateEdge), arg0, arg1, arg2, arg3, arg4)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEdge", reflect.TypeOf((*MockDB)(nil).CreateEdge), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
 // CreateNode mocks base method.
-func (m *MockDB) CreateNode(arg0 context.Context, arg1 User, arg2, arg3 *model.Text) (string, error) {
+func (m *MockDB) CreateNode(arg0 context.Context, arg1 User, arg2, arg3 *model.Text, arg4 bool) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateNode", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "CreateNode", arg0, arg1, arg2, arg3, arg4)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateNode indicates an expected call of CreateNode.
-func (mr *MockDBMockRecorder) CreateNode(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockDBMockRecorder) CreateNode(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNode", reflect.TypeOf((*MockDB)(nil).CreateNode), arg0, arg1, arg2, arg3, arg4)
+}
+
+// CreateResource mocks base method.
+func (m *MockDB) CreateResource(arg0 context.Context, arg1 User, arg2 string, arg3 model.ResourceInput) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateResource", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateResource indicates an expected call of CreateResource.
+func (mr *MockDBMockRecorder) CreateResource(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNode", reflect.TypeOf((*MockDB)(nil).CreateNode), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateResource", reflect.TypeOf((*MockDB)(nil).CreateResource), arg0, arg1, arg2, arg3)
+}
+
+// CreateTag mocks base method.
+func (m *MockDB) CreateTag(arg0 context.Context, arg1 User, arg2 *model.Text) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTag", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTag indicates an expected call of CreateTag.
+func (mr *MockDBMockRecorder) CreateTag(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTag", reflect.TypeOf((*MockDB)(nil).CreateTag), arg0, arg1, arg2)
 }
 
 // CreateUserWithEMail mocks base method.
@@ -136,6 +182,50 @@ func (mr *MockDBMockRecorder) DeleteNode(arg0, arg1, arg2 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNode", reflect.TypeOf((*MockDB)(nil).DeleteNode), arg0, arg1, arg2)
 }
 
+// DeleteResource mocks base method.
+func (m *MockDB) DeleteResource(arg0 context.Context, arg1 User, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteResource", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteResource indicates an expected call of DeleteResource.
+func (mr *MockDBMockRecorder) DeleteResource(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteResource", reflect.TypeOf((*MockDB)(nil).DeleteResource), arg0, arg1, arg2)
+}
+
+// DeletedEdges mocks base method.
+func (m *MockDB) DeletedEdges(arg0 context.Context, arg1 User) ([]*model.DeletedEdge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletedEdges", arg0, arg1)
+	ret0, _ := ret[0].([]*model.DeletedEdge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletedEdges indicates an expected call of DeletedEdges.
+func (mr *MockDBMockRecorder) DeletedEdges(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletedEdges", reflect.TypeOf((*MockDB)(nil).DeletedEdges), arg0, arg1)
+}
+
+// DeletedNodes mocks base method.
+func (m *MockDB) DeletedNodes(arg0 context.Context, arg1 User) ([]*model.DeletedNode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletedNodes", arg0, arg1)
+	ret0, _ := ret[0].([]*model.DeletedNode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletedNodes indicates an expected call of DeletedNodes.
+func (mr *MockDBMockRecorder) DeletedNodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletedNodes", reflect.TypeOf((*MockDB)(nil).DeletedNodes), arg0, arg1)
+}
+
 // EdgeEdits mocks base method.
 func (m *MockDB) EdgeEdits(arg0 context.Context, arg1 string) ([]*model.EdgeEdit, error) {
 	m.ctrl.T.Helper()
@@ -151,6 +241,36 @@ func (mr *MockDBMockRecorder) EdgeEdits(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EdgeEdits", reflect.TypeOf((*MockDB)(nil).EdgeEdits), arg0, arg1)
 }
 
+// EdgeVoteStats mocks base method.
+func (m *MockDB) EdgeVoteStats(arg0 context.Context, arg1 string) (*model.EdgeVoteStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EdgeVoteStats", arg0, arg1)
+	ret0, _ := ret[0].(*model.EdgeVoteStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EdgeVoteStats indicates an expected call of EdgeVoteStats.
+func (mr *MockDBMockRecorder) EdgeVoteStats(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EdgeVoteStats", reflect.TypeOf((*MockDB)(nil).EdgeVoteStats), arg0, arg1)
+}
+
+// EdgeVotes mocks base method.
+func (m *MockDB) EdgeVotes(arg0 context.Context, arg1 User) ([]*model.EdgeVote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EdgeVotes", arg0, arg1)
+	ret0, _ := ret[0].([]*model.EdgeVote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EdgeVotes indicates an expected call of EdgeVotes.
+func (mr *MockDBMockRecorder) EdgeVotes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EdgeVotes", reflect.TypeOf((*MockDB)(nil).EdgeVotes), arg0, arg1)
+}
+
 // EditNode mocks base method.
 func (m *MockDB) EditNode(arg0 context.Context, arg1 User, arg2 string, arg3, arg4 *model.Text) error {
 	m.ctrl.T.Helper()
@@ -165,19 +285,48 @@ func (mr *MockDBMockRecorder) EditNode(arg0, arg1, arg2, arg3, arg4 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditNode", reflect.TypeOf((*MockDB)(nil).EditNode), arg0, arg1, arg2, arg3, arg4)
 }
 
+// EditResource mocks base method.
+func (m *MockDB) EditResource(arg0 context.Context, arg1 User, arg2 string, arg3 model.ResourceInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditResource", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EditResource indicates an expected call of EditResource.
+func (mr *MockDBMockRecorder) EditResource(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditResource", reflect.TypeOf((*MockDB)(nil).EditResource), arg0, arg1, arg2, arg3)
+}
+
 // Graph mocks base method.
-func (m *MockDB) Graph(arg0 context.Context) (*model.Graph, error) {
+func (m *MockDB) Graph(arg0 context.Context, arg1 GraphFilter) (*model.Graph, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Graph", arg0)
+	ret := m.ctrl.Call(m, "Graph", arg0, arg1)
 	ret0, _ := ret[0].(*model.Graph)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Graph indicates an expected call of Graph.
-func (mr *MockDBMockRecorder) Graph(arg0 interface{}) *gomock.Call {
+func (mr *MockDBMockRecorder) Graph(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Graph", reflect.TypeOf((*MockDB)(nil).Graph), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Graph", reflect.TypeOf((*MockDB)(nil).Graph), arg0, arg1)
+}
+
+// GraphOfNodes mocks base method.
+func (m *MockDB) GraphOfNodes(arg0 context.Context, arg1 []string) (*model.Graph, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GraphOfNodes", arg0, arg1)
+	ret0, _ := ret[0].(*model.Graph)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GraphOfNodes indicates an expected call of GraphOfNodes.
+func (mr *MockDBMockRecorder) GraphOfNodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GraphOfNodes", reflect.TypeOf((*MockDB)(nil).GraphOfNodes), arg0, arg1)
 }
 
 // IsUserAuthenticated mocks base method.
@@ -225,6 +374,49 @@ func (mr *MockDBMockRecorder) Logout(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockDB)(nil).Logout), arg0)
 }
 
+// MergeNodes mocks base method.
+func (m *MockDB) MergeNodes(arg0 context.Context, arg1 User, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeNodes", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeNodes indicates an expected call of MergeNodes.
+func (mr *MockDBMockRecorder) MergeNodes(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeNodes", reflect.TypeOf((*MockDB)(nil).MergeNodes), arg0, arg1, arg2, arg3)
+}
+
+// MoveTranslation mocks base method.
+func (m *MockDB) MoveTranslation(arg0 context.Context, arg1 User, arg2, arg3, arg4 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveTranslation", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveTranslation indicates an expected call of MoveTranslation.
+func (mr *MockDBMockRecorder) MoveTranslation(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveTranslation", reflect.TypeOf((*MockDB)(nil).MoveTranslation), arg0, arg1, arg2, arg3, arg4)
+}
+
+// MoveTranslations mocks base method.
+func (m *MockDB) MoveTranslations(arg0 context.Context, arg1 User, arg2 TranslationFilter, arg3, arg4 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveTranslations", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveTranslations indicates an expected call of MoveTranslations.
+func (mr *MockDBMockRecorder) MoveTranslations(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveTranslations", reflect.TypeOf((*MockDB)(nil).MoveTranslations), arg0, arg1, arg2, arg3, arg4)
+}
+
 // Node mocks base method.
 func (m *MockDB) Node(arg0 context.Context, arg1 string) (*model.Node, error) {
 	m.ctrl.T.Helper()
@@ -255,17 +447,308 @@ func (mr *MockDBMockRecorder) NodeEdits(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeEdits", reflect.TypeOf((*MockDB)(nil).NodeEdits), arg0, arg1)
 }
 
+// NodeEditsByID mocks base method.
+func (m *MockDB) NodeEditsByID(arg0 context.Context, arg1 string, arg2 []string) ([]NodeEdit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeEditsByID", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]NodeEdit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NodeEditsByID indicates an expected call of NodeEditsByID.
+func (mr *MockDBMockRecorder) NodeEditsByID(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeEditsByID", reflect.TypeOf((*MockDB)(nil).NodeEditsByID), arg0, arg1, arg2)
+}
+
 // NodeMatchFuzzy mocks base method.
-func (m *MockDB) NodeMatchFuzzy(arg0 context.Context, arg1 string) ([]*model.Node, error) {
+func (m *MockDB) NodeMatchFuzzy(arg0 context.Context, arg1 string, arg2 []string) ([]*model.Node, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "NodeMatchFuzzy", arg0, arg1)
+	ret := m.ctrl.Call(m, "NodeMatchFuzzy", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]*model.Node)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // NodeMatchFuzzy indicates an expected call of NodeMatchFuzzy.
-func (mr *MockDBMockRecorder) NodeMatchFuzzy(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockDBMockRecorder) NodeMatchFuzzy(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeMatchFuzzy", reflect.TypeOf((*MockDB)(nil).NodeMatchFuzzy), arg0, arg1, arg2)
+}
+
+// NodeResources mocks base method.
+func (m *MockDB) NodeResources(arg0 context.Context, arg1 []string) (map[string][]*model.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeResources", arg0, arg1)
+	ret0, _ := ret[0].(map[string][]*model.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NodeResources indicates an expected call of NodeResources.
+func (mr *MockDBMockRecorder) NodeResources(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeResources", reflect.TypeOf((*MockDB)(nil).NodeResources), arg0, arg1)
+}
+
+// PurgeTrash mocks base method.
+func (m *MockDB) PurgeTrash(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrash", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeTrash indicates an expected call of PurgeTrash.
+func (mr *MockDBMockRecorder) PurgeTrash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrash", reflect.TypeOf((*MockDB)(nil).PurgeTrash), arg0, arg1)
+}
+
+// RemoveTranslation mocks base method.
+func (m *MockDB) RemoveTranslation(arg0 context.Context, arg1 User, arg2, arg3 string, arg4 model.TranslationField) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTranslation", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveTranslation indicates an expected call of RemoveTranslation.
+func (mr *MockDBMockRecorder) RemoveTranslation(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTranslation", reflect.TypeOf((*MockDB)(nil).RemoveTranslation), arg0, arg1, arg2, arg3, arg4)
+}
+
+// RemoveTranslations mocks base method.
+func (m *MockDB) RemoveTranslations(arg0 context.Context, arg1 User, arg2 TranslationFilter, arg3 string, arg4 model.TranslationField) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTranslations", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveTranslations indicates an expected call of RemoveTranslations.
+func (mr *MockDBMockRecorder) RemoveTranslations(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTranslations", reflect.TypeOf((*MockDB)(nil).RemoveTranslations), arg0, arg1, arg2, arg3, arg4)
+}
+
+// ResolveEdgeIDs mocks base method.
+func (m *MockDB) ResolveEdgeIDs(arg0 context.Context, arg1 []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveEdgeIDs", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveEdgeIDs indicates an expected call of ResolveEdgeIDs.
+func (mr *MockDBMockRecorder) ResolveEdgeIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveEdgeIDs", reflect.TypeOf((*MockDB)(nil).ResolveEdgeIDs), arg0, arg1)
+}
+
+// ResolveNodeIDs mocks base method.
+func (m *MockDB) ResolveNodeIDs(arg0 context.Context, arg1 []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveNodeIDs", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveNodeIDs indicates an expected call of ResolveNodeIDs.
+func (mr *MockDBMockRecorder) ResolveNodeIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveNodeIDs", reflect.TypeOf((*MockDB)(nil).ResolveNodeIDs), arg0, arg1)
+}
+
+// ResourceEdits mocks base method.
+func (m *MockDB) ResourceEdits(arg0 context.Context, arg1 string) ([]*model.ResourceEdit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceEdits", arg0, arg1)
+	ret0, _ := ret[0].([]*model.ResourceEdit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResourceEdits indicates an expected call of ResourceEdits.
+func (mr *MockDBMockRecorder) ResourceEdits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceEdits", reflect.TypeOf((*MockDB)(nil).ResourceEdits), arg0, arg1)
+}
+
+// RestoreEdge mocks base method.
+func (m *MockDB) RestoreEdge(arg0 context.Context, arg1 User, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreEdge", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreEdge indicates an expected call of RestoreEdge.
+func (mr *MockDBMockRecorder) RestoreEdge(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreEdge", reflect.TypeOf((*MockDB)(nil).RestoreEdge), arg0, arg1, arg2)
+}
+
+// RestoreNode mocks base method.
+func (m *MockDB) RestoreNode(arg0 context.Context, arg1 User, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreNode", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreNode indicates an expected call of RestoreNode.
+func (mr *MockDBMockRecorder) RestoreNode(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreNode", reflect.TypeOf((*MockDB)(nil).RestoreNode), arg0, arg1, arg2)
+}
+
+// RetractEdgeWeightVote mocks base method.
+func (m *MockDB) RetractEdgeWeightVote(arg0 context.Context, arg1 User, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetractEdgeWeightVote", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetractEdgeWeightVote indicates an expected call of RetractEdgeWeightVote.
+func (mr *MockDBMockRecorder) RetractEdgeWeightVote(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetractEdgeWeightVote", reflect.TypeOf((*MockDB)(nil).RetractEdgeWeightVote), arg0, arg1, arg2)
+}
+
+// RevertNode mocks base method.
+func (m *MockDB) RevertNode(arg0 context.Context, arg1 User, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertNode", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevertNode indicates an expected call of RevertNode.
+func (mr *MockDBMockRecorder) RevertNode(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertNode", reflect.TypeOf((*MockDB)(nil).RevertNode), arg0, arg1, arg2, arg3)
+}
+
+// Search mocks base method.
+func (m *MockDB) Search(arg0 context.Context, arg1, arg2 string, arg3, arg4 int) ([]*model.SearchHit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]*model.SearchHit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockDBMockRecorder) Search(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockDB)(nil).Search), arg0, arg1, arg2, arg3, arg4)
+}
+
+// Subgraph mocks base method.
+func (m *MockDB) Subgraph(arg0 context.Context, arg1 string, arg2 int, arg3 model.Direction) (*model.Graph, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subgraph", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.Graph)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subgraph indicates an expected call of Subgraph.
+func (mr *MockDBMockRecorder) Subgraph(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subgraph", reflect.TypeOf((*MockDB)(nil).Subgraph), arg0, arg1, arg2, arg3)
+}
+
+// TagNode mocks base method.
+func (m *MockDB) TagNode(arg0 context.Context, arg1 User, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TagNode", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TagNode indicates an expected call of TagNode.
+func (mr *MockDBMockRecorder) TagNode(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagNode", reflect.TypeOf((*MockDB)(nil).TagNode), arg0, arg1, arg2, arg3)
+}
+
+// Tags mocks base method.
+func (m *MockDB) Tags(arg0 context.Context) ([]*model.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tags", arg0)
+	ret0, _ := ret[0].([]*model.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Tags indicates an expected call of Tags.
+func (mr *MockDBMockRecorder) Tags(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tags", reflect.TypeOf((*MockDB)(nil).Tags), arg0)
+}
+
+// TranslationCoverage mocks base method.
+func (m *MockDB) TranslationCoverage(arg0 context.Context) ([]*model.TranslationCoverage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TranslationCoverage", arg0)
+	ret0, _ := ret[0].([]*model.TranslationCoverage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TranslationCoverage indicates an expected call of TranslationCoverage.
+func (mr *MockDBMockRecorder) TranslationCoverage(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TranslationCoverage", reflect.TypeOf((*MockDB)(nil).TranslationCoverage), arg0)
+}
+
+// UntagNode mocks base method.
+func (m *MockDB) UntagNode(arg0 context.Context, arg1 User, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UntagNode", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UntagNode indicates an expected call of UntagNode.
+func (mr *MockDBMockRecorder) UntagNode(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UntagNode", reflect.TypeOf((*MockDB)(nil).UntagNode), arg0, arg1, arg2, arg3)
+}
+
+// UntranslatedNodes mocks base method.
+func (m *MockDB) UntranslatedNodes(arg0 context.Context, arg1 string, arg2 int, arg3 string) ([]*model.Node, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UntranslatedNodes", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*model.Node)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UntranslatedNodes indicates an expected call of UntranslatedNodes.
+func (mr *MockDBMockRecorder) UntranslatedNodes(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UntranslatedNodes", reflect.TypeOf((*MockDB)(nil).UntranslatedNodes), arg0, arg1, arg2, arg3)
+}
+
+// VoteResource mocks base method.
+func (m *MockDB) VoteResource(arg0 context.Context, arg1 User, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoteResource", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VoteResource indicates an expected call of VoteResource.
+func (mr *MockDBMockRecorder) VoteResource(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeMatchFuzzy", reflect.TypeOf((*MockDB)(nil).NodeMatchFuzzy), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoteResource", reflect.TypeOf((*MockDB)(nil).VoteResource), arg0, arg1, arg2)
 }
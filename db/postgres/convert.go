@@ -1,22 +1,39 @@
 package postgres
 
 import (
+	"context"
+	"sort"
+
 	"github.com/suxatcode/learn-graph-poc-backend/db"
 	"github.com/suxatcode/learn-graph-poc-backend/graph/model"
+	"github.com/suxatcode/learn-graph-poc-backend/middleware"
 )
 
 type ConvertToModel struct {
-	language         string
-	fallbackLanguage string
+	language          string
+	fallbackLanguages []string
 }
 
-const defaultFallbackLanguage = "en"
+const defaultFallbackLanguage = middleware.DefaultLanguage
 
-func NewConvertToModel(language string) *ConvertToModel {
+// NewConvertToModel returns a converter selecting the translation in
+// language, or else in the first available of the fallback languages, the
+// defaultFallbackLanguage or any language, in that order.
+func NewConvertToModel(language string, fallbacks ...string) *ConvertToModel {
 	return &ConvertToModel{
-		language:         language,
-		fallbackLanguage: defaultFallbackLanguage,
+		language:          language,
+		fallbackLanguages: append(append([]string{}, fallbacks...), defaultFallbackLanguage),
+	}
+}
+
+// newConvertToModelFromCtx returns a converter using the fallback chain of
+// languages of the request.
+func newConvertToModelFromCtx(ctx context.Context) *ConvertToModel {
+	languages := middleware.CtxGetLanguages(ctx)
+	if len(languages) == 0 {
+		return NewConvertToModel("")
 	}
+	return NewConvertToModel(languages[0], languages[1:]...)
 }
 
 var LanguageToLanguageFlag = map[string]string{
@@ -29,20 +46,30 @@ var LanguageToLanguageFlag = map[string]string{
 	"ja": "🇯🇵",
 }
 
+// supportedLanguages returns the languages of LanguageToLanguageFlag in sorted
+// order.
+func supportedLanguages() []string {
+	languages := make([]string, 0, len(LanguageToLanguageFlag))
+	for lang := range LanguageToLanguageFlag {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
 func (c *ConvertToModel) getTranslationOrFallback(text db.Text) (string, bool) {
-	returnText, ok := text[c.language]
-	if !ok {
-		returnText, ok = text[c.fallbackLanguage]
-		returnText = LanguageToLanguageFlag[c.fallbackLanguage] + " " + returnText
-		if !ok {
-			for firstExistingLanguage := range text {
-				returnText = text[firstExistingLanguage]
-				returnText = LanguageToLanguageFlag[firstExistingLanguage] + " " + returnText
-				break
-			}
+	if returnText, ok := text[c.language]; ok {
+		return returnText, true
+	}
+	for _, fallback := range c.fallbackLanguages {
+		if returnText, ok := text[fallback]; ok {
+			return LanguageToLanguageFlag[fallback] + " " + returnText, true
 		}
 	}
-	return returnText, ok
+	if languages := languagesOf(text); len(languages) > 0 {
+		return LanguageToLanguageFlag[languages[0]] + " " + text[languages[0]], false
+	}
+	return LanguageToLanguageFlag[defaultFallbackLanguage] + " ", false
 }
 
 func (c *ConvertToModel) Node(node Node) *model.Node {
@@ -51,16 +78,25 @@ func (c *ConvertToModel) Node(node Node) *model.Node {
 	}
 	description, _ := c.getTranslationOrFallback(node.Description)
 	res := model.Node{
-		ID:          itoa(node.ID),
-		Description: description,
+		ID:           itoa(node.ID),
+		Description:  description,
+		Translations: c.nodeTranslations(node.Description, node.Resources),
 	}
 	resources, ok := c.getTranslationOrFallback(node.Resources)
 	if ok {
 		res.Resources = &resources
 	}
+	for _, tag := range node.Tags {
+		res.Tags = append(res.Tags, c.Tag(tag))
+	}
 	return &res
 }
 
+func (c *ConvertToModel) Tag(tag Tag) *model.Tag {
+	name, _ := c.getTranslationOrFallback(tag.Name)
+	return &model.Tag{ID: itoa(tag.ID), Name: name}
+}
+
 func (c *ConvertToModel) Graph(nodes []Node, edges []Edge) *model.Graph {
 	g := model.Graph{}
 	for _, n := range nodes {
@@ -71,30 +107,40 @@ func (c *ConvertToModel) Graph(nodes []Node, edges []Edge) *model.Graph {
 		g.Nodes = append(g.Nodes, node)
 	}
 	for _, e := range edges {
-		g.Edges = append(g.Edges, &model.Edge{
-			ID:     itoa(e.ID),
-			From:   itoa(e.FromID),
-			To:     itoa(e.ToID),
-			Weight: e.Weight,
-		})
+		g.Edges = append(g.Edges, c.Edge(e))
 	}
 	return &g
 }
 
+func (c *ConvertToModel) Edge(e Edge) *model.Edge {
+	return &model.Edge{
+		ID:     itoa(e.ID),
+		From:   itoa(e.FromID),
+		To:     itoa(e.ToID),
+		Weight: e.Weight,
+		Type:   model.EdgeType(e.Type),
+	}
+}
+
 func (c *ConvertToModel) NodeEdits(edits []NodeEdit) []*model.NodeEdit {
 	modelEdits := make([]*model.NodeEdit, 0, len(edits))
 	for _, edit := range edits {
 		newDescription, _ := c.getTranslationOrFallback(edit.NewDescription)
 		modelEdit := model.NodeEdit{
+			ID:             itoa(edit.ID),
 			Username:       edit.User.Username,
 			Type:           model.NodeEditType(edit.Type),
 			NewDescription: newDescription,
 			UpdatedAt:      edit.CreatedAt,
+			Translations:   c.nodeTranslations(edit.NewDescription, edit.NewResources),
 		}
 		newResrouces, ok := c.getTranslationOrFallback(edit.NewResources)
 		if ok {
 			modelEdit.NewResources = &newResrouces
 		}
+		if edit.Tag != nil {
+			modelEdit.Tag = c.Tag(*edit.Tag)
+		}
 		modelEdits = append(modelEdits, &modelEdit)
 	}
 	return modelEdits
@@ -109,11 +155,65 @@ func (c *ConvertToModel) EdgeEdits(edits []EdgeEdit) []*model.EdgeEdit {
 			Weight:    edit.Weight,
 			UpdatedAt: edit.CreatedAt,
 		}
+		if edit.EdgeType != "" {
+			edgeType := model.EdgeType(edit.EdgeType)
+			modelEdit.EdgeType = &edgeType
+		}
 		modelEdits = append(modelEdits, &modelEdit)
 	}
 	return modelEdits
 }
 
+func (c *ConvertToModel) Resource(resource Resource) *model.Resource {
+	title, _ := c.getTranslationOrFallback(resource.Title)
+	return &model.Resource{
+		ID:              itoa(resource.ID),
+		URL:             resource.URL,
+		Title:           title,
+		Kind:            model.ResourceKind(resource.Kind),
+		Language:        resource.Language,
+		DurationMinutes: resource.DurationMinutes,
+		Votes:           resource.Votes,
+		Translations:    c.Translations(model.TranslationFieldTitle, resource.Title),
+	}
+}
+
+func (c *ConvertToModel) ResourceEdits(edits []ResourceEdit) []*model.ResourceEdit {
+	modelEdits := make([]*model.ResourceEdit, 0, len(edits))
+	for _, edit := range edits {
+		title, _ := c.getTranslationOrFallback(edit.Title)
+		modelEdits = append(modelEdits, &model.ResourceEdit{
+			ID:              itoa(edit.ID),
+			Username:        edit.User.Username,
+			Type:            model.ResourceEditType(edit.Type),
+			URL:             edit.URL,
+			Title:           title,
+			Kind:            model.ResourceKind(edit.Kind),
+			Language:        edit.Language,
+			DurationMinutes: edit.DurationMinutes,
+			UpdatedAt:       edit.CreatedAt,
+		})
+	}
+	return modelEdits
+}
+
+// Translations returns every translation of a text without flag prefixes,
+// ordered by language.
+func (c *ConvertToModel) Translations(field model.TranslationField, text db.Text) []*model.TranslationOut {
+	translations := make([]*model.TranslationOut, 0, len(text))
+	for _, lang := range languagesOf(text) {
+		translations = append(translations, &model.TranslationOut{Language: lang, Field: field, Content: text[lang]})
+	}
+	return translations
+}
+
+func (c *ConvertToModel) nodeTranslations(description, resources db.Text) []*model.TranslationOut {
+	return append(
+		c.Translations(model.TranslationFieldDescription, description),
+		c.Translations(model.TranslationFieldResources, resources)...,
+	)
+}
+
 func ConvertToDBText(text *model.Text) db.Text {
 	if text == nil {
 		return db.Text{}
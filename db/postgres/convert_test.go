@@ -1,14 +1,28 @@
 package postgres
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/suxatcode/learn-graph-poc-backend/db"
 	"github.com/suxatcode/learn-graph-poc-backend/graph/model"
+	"github.com/suxatcode/learn-graph-poc-backend/middleware"
 	"gorm.io/gorm"
 )
 
+// translationsOf returns the translations of a node, see ConvertToModel.Node.
+func translationsOf(description, resources db.Text) []*model.TranslationOut {
+	translations := []*model.TranslationOut{}
+	for _, lang := range languagesOf(description) {
+		translations = append(translations, &model.TranslationOut{Language: lang, Field: model.TranslationFieldDescription, Content: description[lang]})
+	}
+	for _, lang := range languagesOf(resources) {
+		translations = append(translations, &model.TranslationOut{Language: lang, Field: model.TranslationFieldResources, Content: resources[lang]})
+	}
+	return translations
+}
+
 func TestConvertToModelGraph(t *testing.T) {
 	for _, test := range []struct {
 		Name     string
@@ -23,7 +37,7 @@ func TestConvertToModelGraph(t *testing.T) {
 			Language: "en",
 			Exp: &model.Graph{
 				Nodes: []*model.Node{
-					{ID: "123", Description: "a"},
+					{ID: "123", Description: "a", Translations: translationsOf(db.Text{"en": "a"}, nil)},
 				},
 			},
 		},
@@ -36,8 +50,8 @@ func TestConvertToModelGraph(t *testing.T) {
 			Language: "en",
 			Exp: &model.Graph{
 				Nodes: []*model.Node{
-					{ID: "123", Description: "a"},
-					{ID: "456", Description: "a"},
+					{ID: "123", Description: "a", Translations: translationsOf(db.Text{"en": "a"}, nil)},
+					{ID: "456", Description: "a", Translations: translationsOf(db.Text{"en": "a"}, nil)},
 				},
 			},
 		},
@@ -53,8 +67,8 @@ func TestConvertToModelGraph(t *testing.T) {
 			Language: "en",
 			Exp: &model.Graph{
 				Nodes: []*model.Node{
-					{ID: "1", Description: "a"},
-					{ID: "2", Description: "b"},
+					{ID: "1", Description: "a", Translations: translationsOf(db.Text{"en": "a"}, nil)},
+					{ID: "2", Description: "b", Translations: translationsOf(db.Text{"en": "b"}, nil)},
 				},
 				Edges: []*model.Edge{
 					{ID: "3", From: "1", To: "2"},
@@ -67,7 +81,7 @@ func TestConvertToModelGraph(t *testing.T) {
 			Language: "ch",
 			Exp: &model.Graph{
 				Nodes: []*model.Node{
-					{ID: "1", Description: "🇺🇸 ok"},
+					{ID: "1", Description: "🇺🇸 ok", Translations: translationsOf(db.Text{"en": "ok"}, nil)},
 				},
 			},
 		},
@@ -83,7 +97,7 @@ func TestConvertToModelGraph(t *testing.T) {
 			Language: "en",
 			Exp: &model.Graph{
 				Nodes: []*model.Node{
-					{ID: "1", Description: "🇹🇼 打坐"},
+					{ID: "1", Description: "🇹🇼 打坐", Translations: translationsOf(db.Text{"zh": "打坐"}, nil)},
 				},
 			},
 		},
@@ -93,7 +107,10 @@ func TestConvertToModelGraph(t *testing.T) {
 			Language: "zh",
 			Exp: &model.Graph{
 				Nodes: []*model.Node{
-					{ID: "1", Description: "打坐", Resources: strptr("🇺🇸 A")},
+					{ID: "1", Description: "打坐", Resources: strptr("🇺🇸 A"), Translations: []*model.TranslationOut{
+						{Language: "zh", Field: model.TranslationFieldDescription, Content: "打坐"},
+						{Language: "en", Field: model.TranslationFieldResources, Content: "A"},
+					}},
 				},
 			},
 		},
@@ -104,6 +121,45 @@ func TestConvertToModelGraph(t *testing.T) {
 	}
 }
 
+func TestConvertToModel_FallbackChain(t *testing.T) {
+	text := db.Text{"de": "Apfel", "en": "Apple", "es": "Manzana", "ja": "りんご"}
+	for _, test := range []struct {
+		Name      string
+		Languages []string
+		Text      db.Text
+		Exp       string
+		ExpOK     bool
+	}{
+		{Name: "requested language", Languages: []string{"de"}, Text: text, Exp: "Apfel", ExpOK: true},
+		{Name: "regional variant falls back to primary language", Languages: []string{"de-AT", "de", "en"}, Text: text, Exp: "🇩🇪 Apfel", ExpOK: true},
+		{Name: "fallbacks in order of preference", Languages: []string{"fr", "es", "en"}, Text: text, Exp: "🇪🇸 Manzana", ExpOK: true},
+		{Name: "default fallback", Languages: []string{"fr"}, Text: text, Exp: "🇺🇸 Apple", ExpOK: true},
+		{Name: "any language", Languages: []string{"fr"}, Text: db.Text{"zh": "苹果", "ja": "りんご"}, Exp: "🇯🇵 りんご"},
+		{Name: "no translation", Languages: []string{"fr"}, Text: db.Text{}, Exp: "🇺🇸 "},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx := middleware.TestingCtxNewWithLanguages(context.Background(), test.Languages...)
+			res, ok := newConvertToModelFromCtx(ctx).getTranslationOrFallback(test.Text)
+			assert.Equal(t, test.Exp, res)
+			assert.Equal(t, test.ExpOK, ok)
+		})
+	}
+}
+
+func TestConvertToModel_Translations(t *testing.T) {
+	converter := NewConvertToModel("de")
+	assert.Equal(t, []*model.TranslationOut{
+		{Language: "de", Field: model.TranslationFieldDescription, Content: "Apfel"},
+		{Language: "en", Field: model.TranslationFieldDescription, Content: "Apple"},
+		{Language: "zh", Field: model.TranslationFieldDescription, Content: "苹果"},
+	}, converter.Translations(model.TranslationFieldDescription, db.Text{"zh": "苹果", "en": "Apple", "de": "Apfel"}))
+	assert.Equal(t, []*model.TranslationOut{}, converter.Translations(model.TranslationFieldResources, nil))
+}
+
+func TestLanguageToLanguageFlag(t *testing.T) {
+	assert.Equal(t, middleware.SupportedLanguages, supportedLanguages(), "every supported language needs a flag")
+}
+
 func TestConvertToDBText(t *testing.T) {
 	for _, test := range []struct {
 		Name string
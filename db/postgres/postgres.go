@@ -5,17 +5,23 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
+	"html"
 	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/suxatcode/learn-graph-poc-backend/db"
+	"github.com/suxatcode/learn-graph-poc-backend/db/aggregate"
 	"github.com/suxatcode/learn-graph-poc-backend/graph/model"
 	"github.com/suxatcode/learn-graph-poc-backend/middleware"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 const (
@@ -23,6 +29,8 @@ const (
 	AUTH_TOKEN_LENGTH           = 64                       // bytes
 	MIN_PASSWORD_LENGTH         = 10
 	MIN_USERNAME_LENGTH         = 4
+	DUPLICATE_NODE_SIMILARITY   = 0.6 // pg_trgm similarity in [0,1]
+	DUPLICATE_NODE_LIMIT        = 5   // per language
 )
 
 var TESTONLY_Config = db.Config{PGHost: "localhost"}
@@ -31,6 +39,61 @@ type Node struct {
 	gorm.Model
 	Description db.Text `gorm:"type:jsonb;default:'{}';not null"`
 	Resources   db.Text `gorm:"type:jsonb"`
+	Tags        []Tag   `gorm:"many2many:node_tags;constraint:OnDelete:CASCADE"`
+}
+type Tag struct {
+	gorm.Model
+	Name db.Text `gorm:"type:jsonb;default:'{}';not null"`
+}
+
+// NodeRedirect points from the ID of a node merged into another node to the
+// ID of that node.
+type NodeRedirect struct {
+	gorm.Model
+	FromID uint `gorm:"uniqueIndex;not null"`
+	ToID   uint
+	To     Node `gorm:"constraint:OnDelete:CASCADE;not null"`
+}
+
+// EdgeRedirect points from the ID of an edge folded into another edge, when
+// merging their nodes, to the ID of that edge.
+type EdgeRedirect struct {
+	gorm.Model
+	FromID uint `gorm:"uniqueIndex;not null"`
+	ToID   uint
+	To     Edge `gorm:"constraint:OnDelete:CASCADE;not null"`
+}
+type Resource struct {
+	gorm.Model
+	NodeID          uint
+	Node            Node            `gorm:"constraint:OnDelete:CASCADE;not null"`
+	URL             string          `gorm:"not null"`
+	Title           db.Text         `gorm:"type:jsonb;default:'{}';not null"`
+	Kind            db.ResourceKind `gorm:"type:text;not null"`
+	Language        string          `gorm:"not null"`
+	DurationMinutes *int
+	// Votes is the number of ResourceVotes, kept here for sorting
+	Votes int `gorm:"not null;default:0"`
+}
+type ResourceEdit struct {
+	gorm.Model
+	ResourceID      uint
+	Resource        Resource `gorm:"constraint:OnDelete:CASCADE;not null"`
+	UserID          uint
+	User            User                `gorm:"constraint:OnDelete:SET DEFAULT;not null"`
+	Type            db.ResourceEditType `gorm:"type:text;not null"`
+	URL             string
+	Title           db.Text         `gorm:"type:jsonb;default:'{}';not null"`
+	Kind            db.ResourceKind `gorm:"type:text;not null"`
+	Language        string
+	DurationMinutes *int
+}
+type ResourceVote struct {
+	gorm.Model
+	ResourceID uint     `gorm:"index:noDuplicateResourceVotes,unique"`
+	Resource   Resource `gorm:"constraint:OnDelete:CASCADE;not null"`
+	UserID     uint     `gorm:"index:noDuplicateResourceVotes,unique"`
+	User       User     `gorm:"constraint:OnDelete:CASCADE;not null"`
 }
 type NodeEdit struct {
 	gorm.Model
@@ -41,14 +104,21 @@ type NodeEdit struct {
 	Type           db.NodeEditType `gorm:"type:text;not null"`
 	NewDescription db.Text         `gorm:"type:jsonb;default:'{}';not null"`
 	NewResources   db.Text         `gorm:"type:jsonb"`
+	// TagID is the tag added or removed by edits of type tag and untag
+	TagID *uint
+	Tag   *Tag `gorm:"constraint:OnDelete:SET NULL"`
 }
 type Edge struct {
 	gorm.Model
-	FromID uint `gorm:"index:noDuplicateEdges,unique;"`
-	ToID   uint `gorm:"index:noDuplicateEdges,unique;"`
+	// deleted edges stay in the trash, hence only active edges are unique
+	FromID uint `gorm:"index:noDuplicateActiveEdges,unique,where:deleted_at IS NULL;"`
+	ToID   uint `gorm:"index:noDuplicateActiveEdges,unique,where:deleted_at IS NULL;"`
 	From   Node `gorm:"constraint:OnDelete:CASCADE;not null"`
 	To     Node `gorm:"constraint:OnDelete:CASCADE;not null"`
 	Weight float64
+	Type   db.EdgeType `gorm:"type:text;not null;default:'prerequisite'"`
+	// WeightDirty is set, while votes are pending aggregation into Weight
+	WeightDirty bool `gorm:"not null;default:false;index:,where:weight_dirty"`
 }
 type EdgeEdit struct {
 	gorm.Model
@@ -58,6 +128,8 @@ type EdgeEdit struct {
 	User   User            `gorm:"constraint:OnDelete:SET DEFAULT;not null"`
 	Type   db.EdgeEditType `gorm:"type:text;not null"`
 	Weight float64
+	// EdgeType is the type of the edge at the time of the edit
+	EdgeType db.EdgeType `gorm:"type:text"`
 }
 type User struct {
 	gorm.Model
@@ -106,33 +178,49 @@ func NewPostgresDB(conf db.Config) (db.DB, error) {
 		timeNow:  time.Now,
 		newToken: makeStringToken,
 	}
+	pg.aggregator, err = aggregate.New(aggregate.Config{
+		Strategy:     conf.VoteAggregation,
+		TrimFraction: conf.VoteTrimFraction,
+		HalfLife:     conf.VoteHalfLife,
+		Now:          func() time.Time { return pg.timeNow() },
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid vote aggregation")
+	}
 	return pg.init()
 }
 
 // implements db.DB
 type PostgresDB struct {
-	db       *gorm.DB
-	timeNow  func() time.Time
-	newToken func() string
+	db         *gorm.DB
+	timeNow    func() time.Time
+	newToken   func() string
+	aggregator aggregate.Aggregator
 }
 
 func (pg *PostgresDB) init() (db.DB, error) {
 	// Auto-migrate the models
 	err := pg.db.AutoMigrate(
-		&Node{}, &Edge{}, &NodeEdit{}, &EdgeEdit{}, &AuthenticationToken{}, &User{}, &Role{},
+		&Node{}, &Edge{}, &NodeEdit{}, &EdgeEdit{}, &AuthenticationToken{}, &User{}, &Role{}, &Tag{},
+		&Resource{}, &ResourceEdit{}, &ResourceVote{}, &NodeRedirect{}, &EdgeRedirect{},
 	)
 	if err != nil {
 		return nil, err
 	}
+	// replaced by noDuplicateActiveEdges, which ignores edges in the trash
+	err = pg.db.Exec(`DROP INDEX IF EXISTS "noDuplicateEdges";`).Error
+	if err != nil {
+		return nil, err
+	}
 	err = pg.db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm;").Error
 	if err != nil {
 		return nil, err
 	}
-	// TODO(skep): only 'en' language is indexed right now! do it for all languages dynamically?!
-	err = pg.db.Exec(`
-        CREATE INDEX IF NOT EXISTS idx_nodes_description_text_trgm
-        ON nodes USING GIN ((description->>'en') gin_trgm_ops);
-    `).Error
+	err = ensureSearchColumns(pg.db)
+	if err != nil {
+		return nil, err
+	}
+	err = ensureTrigramIndexes(pg.db, supportedLanguages()...)
 	if err != nil {
 		return nil, err
 	}
@@ -161,7 +249,13 @@ func (pg *PostgresDB) ReplaceAllDataWith(ctx context.Context, data db.AllData) e
 			`DROP TABLE IF EXISTS users CASCADE`,
 			`DROP TABLE IF EXISTS edge_edits CASCADE`,
 			`DROP TABLE IF EXISTS edges CASCADE`,
+			`DROP TABLE IF EXISTS resource_votes CASCADE`,
+			`DROP TABLE IF EXISTS resource_edits CASCADE`,
+			`DROP TABLE IF EXISTS resources CASCADE`,
+			`DROP TABLE IF EXISTS node_redirects CASCADE`,
 			`DROP TABLE IF EXISTS node_edits CASCADE`,
+			`DROP TABLE IF EXISTS node_tags CASCADE`,
+			`DROP TABLE IF EXISTS tags CASCADE`,
 			`DROP TABLE IF EXISTS nodes CASCADE`,
 		} {
 			if err := tx.Exec(stmt).Error; err != nil {
@@ -259,16 +353,108 @@ func (pg *PostgresDB) ReplaceAllDataWith(ctx context.Context, data db.AllData) e
 	})
 }
 
-func (pg *PostgresDB) Graph(ctx context.Context) (*model.Graph, error) {
+// textSearchConfigs maps the languages with a full-text search column to
+// their postgres text search configuration.
+var textSearchConfigs = map[string]string{
+	"en": "english",
+	"de": "german",
+	"es": "spanish",
+	"fr": "french",
+	"it": "italian",
+	"zh": "simple", // no stemming available
+	"ja": "simple", // no stemming available
+}
+
+func searchColumn(lang string) string {
+	return "search_" + lang
+}
+
+// ensureSearchColumns adds a generated tsvector column with a GIN index per
+// language in textSearchConfigs, the description is weighted above the
+// resources.
+func ensureSearchColumns(tx *gorm.DB) error {
+	languages := []string{}
+	for lang := range textSearchConfigs {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	for _, lang := range languages {
+		config := textSearchConfigs[lang]
+		for _, stmt := range []string{
+			fmt.Sprintf(`ALTER TABLE nodes ADD COLUMN IF NOT EXISTS %s tsvector GENERATED ALWAYS AS (
+				setweight(to_tsvector('%s', coalesce(description->>'%s', '')), 'A') ||
+				setweight(to_tsvector('%s', coalesce(resources->>'%s', '')), 'B')
+			) STORED`, searchColumn(lang), config, lang, config, lang),
+			fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_nodes_%s ON nodes USING GIN (%s)`, searchColumn(lang), searchColumn(lang)),
+		} {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return errors.Wrapf(err, "failed to create search column for language '%s'", lang)
+			}
+		}
+	}
+	return nil
+}
+
+// languageCodeRegexp matches language codes, that are safe to be used in
+// index names and SQL expressions, e.g. "en", "zh-TW".
+var languageCodeRegexp = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})?$`)
+
+// descriptionOf returns the SQL expression selecting the description of a node
+// in language lang, which must match languageCodeRegexp.
+func descriptionOf(lang string) string {
+	return fmt.Sprintf("(description->>'%s')", lang)
+}
+
+func trigramIndexName(lang string) string {
+	if lang == defaultFallbackLanguage {
+		return "idx_nodes_description_text_trgm" // name of the index before all languages were indexed
+	}
+	return fmt.Sprintf("idx_nodes_description_%s_trgm", strings.ToLower(strings.ReplaceAll(lang, "-", "_")))
+}
+
+// ensureTrigramIndexes creates the trigram indexes used by NodeMatchFuzzy for
+// the given languages, if they do not exist yet. Languages not matching
+// languageCodeRegexp are skipped. It is only called by init, since creating
+// an index blocks writes to the nodes.
+func ensureTrigramIndexes(tx *gorm.DB, languages ...string) error {
+	for _, lang := range languages {
+		if !languageCodeRegexp.MatchString(lang) {
+			continue
+		}
+		var exists int64
+		if err := tx.Raw(`SELECT COUNT(*) FROM pg_indexes WHERE indexname = ?`, trigramIndexName(lang)).Scan(&exists).Error; err != nil {
+			return err
+		}
+		if exists > 0 {
+			continue
+		}
+		stmt := fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON nodes USING GIN (%s gin_trgm_ops)`, trigramIndexName(lang), descriptionOf(lang))
+		if err := tx.Exec(stmt).Error; err != nil {
+			return errors.Wrapf(err, "failed to create trigram index for language '%s'", lang)
+		}
+	}
+	return nil
+}
+
+func (pg *PostgresDB) Graph(ctx context.Context, filter db.GraphFilter) (*model.Graph, error) {
 	var (
 		nodes []Node
 		edges []Edge
 	)
 	err := pg.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Find(&nodes).Error; err != nil {
+		nodeQuery, edgeQuery := tx.Preload("Tags"), tx
+		if len(filter.TagIDs) > 0 {
+			tagged := tx.Table("node_tags").Select("node_id").Where("tag_id IN ?", atoiAll(filter.TagIDs))
+			nodeQuery = nodeQuery.Where("id IN (?)", tagged)
+			edgeQuery = edgeQuery.Where("from_id IN (?) AND to_id IN (?)", tagged, tagged)
+		}
+		if err := nodeQuery.Find(&nodes).Error; err != nil {
 			return err
 		}
-		if err := tx.Find(&edges).Error; err != nil {
+		if len(filter.EdgeTypes) > 0 {
+			edgeQuery = edgeQuery.Where("type IN ?", filter.EdgeTypes)
+		}
+		if err := edgeQuery.Find(&edges).Error; err != nil {
 			return err
 		}
 		return nil
@@ -276,23 +462,153 @@ func (pg *PostgresDB) Graph(ctx context.Context) (*model.Graph, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to read graph")
 	}
-	lang := middleware.CtxGetLanguage(ctx)
-	graph := NewConvertToModel(lang).Graph(nodes, edges)
+	graph := newConvertToModelFromCtx(ctx).Graph(nodes, edges)
 	return graph, nil
 }
 
+func (pg *PostgresDB) Subgraph(ctx context.Context, center string, depth int, direction model.Direction) (*model.Graph, error) {
+	if depth < 0 {
+		return nil, errors.Errorf("depth must not be negative, got %d", depth)
+	}
+	if !direction.IsValid() {
+		return nil, errors.Errorf("invalid direction '%s'", direction)
+	}
+	var (
+		nodes []Node
+		edges []Edge
+	)
+	err := pg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		centerNode := Node{}
+		if err := tx.First(&centerNode, atoi(center)).Error; err != nil {
+			return err
+		}
+		ids, err := neighborhood(tx, centerNode.ID, depth, direction)
+		if err != nil {
+			return err
+		}
+		nodes, edges, err = graphOfNodeIDs(tx, ids)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read subgraph of node '%s'", center)
+	}
+	return newConvertToModelFromCtx(ctx).Graph(nodes, edges), nil
+}
+
+func (pg *PostgresDB) GraphOfNodes(ctx context.Context, nodeIDs []string) (*model.Graph, error) {
+	ids := make([]uint, 0, len(nodeIDs))
+	for _, id := range nodeIDs {
+		ids = append(ids, atoi(id))
+	}
+	var (
+		nodes []Node
+		edges []Edge
+	)
+	err := pg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		nodes, edges, err = graphOfNodeIDs(tx, ids)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read graph of nodes")
+	}
+	return newConvertToModelFromCtx(ctx).Graph(nodes, edges), nil
+}
+
+// neighborhood returns the IDs of all nodes within `depth` edges of node
+// `center` in breadth first order, only the edges adjacent to the current
+// frontier are queried.
+func neighborhood(tx *gorm.DB, center uint, depth int, direction model.Direction) ([]uint, error) {
+	ids := []uint{center}
+	seen := map[uint]bool{center: true}
+	frontier := []uint{center}
+	for hop := 0; hop < depth && len(frontier) > 0; hop++ {
+		query := tx.Select("from_id", "to_id").Order("id")
+		switch direction {
+		case model.DirectionOut:
+			query = query.Where("from_id IN ?", frontier)
+		case model.DirectionIn:
+			query = query.Where("to_id IN ?", frontier)
+		case model.DirectionBoth:
+			query = query.Where("from_id IN ? OR to_id IN ?", frontier, frontier)
+		}
+		edges := []Edge{}
+		if err := query.Find(&edges).Error; err != nil {
+			return nil, err
+		}
+		next := []uint{}
+		for _, edge := range edges {
+			for _, id := range []uint{edge.FromID, edge.ToID} {
+				if seen[id] {
+					continue
+				}
+				seen[id] = true
+				next = append(next, id)
+			}
+		}
+		ids = append(ids, next...)
+		frontier = next
+	}
+	return ids, nil
+}
+
+// graphOfNodeIDs loads the nodes with the given IDs and all edges between
+// them.
+func graphOfNodeIDs(tx *gorm.DB, ids []uint) ([]Node, []Edge, error) {
+	nodes, edges := []Node{}, []Edge{}
+	if len(ids) == 0 {
+		return nodes, edges, nil
+	}
+	if err := tx.Where("id IN ?", ids).Order("id").Find(&nodes).Error; err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Where("from_id IN ? AND to_id IN ?", ids, ids).Order("id").Find(&edges).Error; err != nil {
+		return nil, nil, err
+	}
+	return nodes, edges, nil
+}
+
 func (pg *PostgresDB) Node(ctx context.Context, ID string) (*model.Node, error) {
 	node := Node{}
-	if err := pg.db.First(&node).Error; err != nil {
+	if err := pg.db.Preload("Tags").First(&node, atoi(ID)).Error; err != nil {
 		return nil, err
 	}
-	lang := middleware.CtxGetLanguage(ctx)
-	return NewConvertToModel(lang).Node(node), nil
+	return newConvertToModelFromCtx(ctx).Node(node), nil
+}
+
+// verifyLanguages returns an error for translations in unsupported languages,
+// only the supported languages are indexed for NodeMatchFuzzy.
+func verifyLanguages(texts ...*model.Text) error {
+	for _, text := range texts {
+		for lang := range db.ConvertToDBText(text) {
+			if _, ok := LanguageToLanguageFlag[lang]; !ok {
+				return errors.Errorf("unsupported language '%s'", lang)
+			}
+		}
+	}
+	return nil
 }
 
-func (pg *PostgresDB) CreateNode(ctx context.Context, user db.User, description, resources *model.Text) (string, error) {
+func (pg *PostgresDB) CreateNode(ctx context.Context, user db.User, description, resources *model.Text, force bool) (string, error) {
+	if err := verifyLanguages(description, resources); err != nil {
+		return "", err
+	}
 	node := Node{Description: db.ConvertToDBText(description), Resources: db.ConvertToDBText(resources)}
 	err := pg.db.Transaction(func(tx *gorm.DB) error {
+		if !force {
+			duplicates, err := similarNodes(tx, node.Description)
+			if err != nil {
+				return err
+			}
+			if len(duplicates) > 0 {
+				converter := newConvertToModelFromCtx(ctx)
+				duplicateErr := &db.DuplicateNodesError{}
+				for _, duplicate := range duplicates {
+					duplicateErr.Duplicates = append(duplicateErr.Duplicates, converter.Node(duplicate))
+				}
+				return duplicateErr
+			}
+		}
 		if err := tx.Create(&node).Error; err != nil {
 			return err
 		}
@@ -310,21 +626,59 @@ func (pg *PostgresDB) CreateNode(ctx context.Context, user db.User, description,
 	})
 	return itoa(node.ID), err
 }
-func (pg *PostgresDB) CreateEdge(ctx context.Context, user db.User, from, to string, weight float64) (string, error) {
+
+// similarNodes returns the nodes whose description is similar to the given
+// description in any of its languages, ordered by ID.
+func similarNodes(tx *gorm.DB, description db.Text) ([]Node, error) {
+	seen := map[uint]bool{}
+	result := []Node{}
+	for _, lang := range languagesOf(description) {
+		text := strings.ToLower(description[lang])
+		if text == "" {
+			continue
+		}
+		nodes := []Node{}
+		err := tx.
+			Where("(description->>?) % ?", lang, text). // % is the similarity operator of pg_trgm
+			Where("similarity(description->>?, ?) >= ?", lang, text, DUPLICATE_NODE_SIMILARITY).
+			Order(clause.Expr{SQL: "similarity(description->>?, ?) DESC", Vars: []interface{}{lang, text}}).
+			Limit(DUPLICATE_NODE_LIMIT).
+			Find(&nodes).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range nodes {
+			if seen[node.ID] {
+				continue
+			}
+			seen[node.ID] = true
+			result = append(result, node)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func (pg *PostgresDB) CreateEdge(ctx context.Context, user db.User, from, to string, weight float64, edgeType db.EdgeType) (string, error) {
 	edge := Edge{
 		FromID: atoi(from),
 		ToID:   atoi(to),
 		Weight: weight,
+		Type:   edgeType,
 	}
 	err := pg.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkEdgeCreatesNoCycle(tx, edge); err != nil {
+			return err
+		}
 		if err := tx.Create(&edge).Error; err != nil {
 			return err
 		}
 		edgeedit := EdgeEdit{
-			EdgeID: edge.ID,
-			UserID: atoi(user.Key),
-			Type:   db.EdgeEditTypeCreate,
-			Weight: weight,
+			EdgeID:   edge.ID,
+			UserID:   atoi(user.Key),
+			Type:     db.EdgeEditTypeCreate,
+			Weight:   weight,
+			EdgeType: edgeType,
 		}
 		if err := tx.Create(&edgeedit).Error; err != nil {
 			return err
@@ -333,7 +687,79 @@ func (pg *PostgresDB) CreateEdge(ctx context.Context, user db.User, from, to str
 	})
 	return itoa(edge.ID), err
 }
+
+// prerequisiteEdgesLock is the key of the advisory lock, that serializes the
+// transactions adding prerequisite edges. Otherwise two transactions adding
+// A->B and B->A concurrently would both find no cycle.
+const prerequisiteEdgesLock = 0x4c47_0001
+
+// checkEdgeCreatesNoCycle returns a *db.CycleError if the prerequisite edge
+// from node `from` to node `to` would close a cycle of prerequisites, i.e. if
+// `from` is reachable from `to`. Other edge types may form cycles.
+// The transaction holds prerequisiteEdgesLock from then on, so the edge must
+// be added within the same transaction.
+func checkEdgeCreatesNoCycle(tx *gorm.DB, edge Edge) error {
+	if edge.Type != db.EdgeTypePrerequisite {
+		return nil
+	}
+	from, to := edge.FromID, edge.ToID
+	if from == to {
+		return &db.CycleError{Cycle: []string{itoa(from), itoa(to)}}
+	}
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", prerequisiteEdgesLock).Error; err != nil {
+		return errors.Wrap(err, "failed to lock prerequisite edges")
+	}
+	path, err := findPath(tx, to, from)
+	if err != nil {
+		return errors.Wrap(err, "failed to check for cycles")
+	}
+	if path == nil {
+		return nil
+	}
+	cycle := []string{itoa(from)}
+	for _, id := range path {
+		cycle = append(cycle, itoa(id))
+	}
+	return &db.CycleError{Cycle: cycle}
+}
+
+// findPath returns the node IDs along a directed path of prerequisite edges
+// from node `from` to node `to` (both included), or nil if there is no such
+// path.
+// The search is a breadth first search, that queries only the edges leaving
+// the current frontier, instead of loading all edges.
+func findPath(tx *gorm.DB, from, to uint) ([]uint, error) {
+	parent := map[uint]uint{from: from}
+	frontier := []uint{from}
+	for len(frontier) > 0 {
+		edges := []Edge{}
+		if err := tx.Select("from_id", "to_id").Where("from_id IN ? AND type = ?", frontier, db.EdgeTypePrerequisite).Order("id").Find(&edges).Error; err != nil {
+			return nil, err
+		}
+		next := []uint{}
+		for _, edge := range edges {
+			if _, seen := parent[edge.ToID]; seen {
+				continue
+			}
+			parent[edge.ToID] = edge.FromID
+			if edge.ToID == to {
+				path := []uint{to}
+				for node := to; node != from; node = parent[node] {
+					path = append([]uint{parent[node]}, path...)
+				}
+				return path, nil
+			}
+			next = append(next, edge.ToID)
+		}
+		frontier = next
+	}
+	return nil, nil
+}
+
 func (pg *PostgresDB) EditNode(ctx context.Context, user db.User, nodeID string, description, resources *model.Text) error {
+	if err := verifyLanguages(description, resources); err != nil {
+		return err
+	}
 	return pg.db.Transaction(func(tx *gorm.DB) error {
 		node := Node{Model: gorm.Model{ID: atoi(nodeID)}}
 		if err := tx.First(&node).Error; err != nil {
@@ -349,6 +775,7 @@ func (pg *PostgresDB) EditNode(ctx context.Context, user db.User, nodeID string,
 			UserID:         atoi(user.Key),
 			Type:           db.NodeEditTypeEdit,
 			NewDescription: node.Description,
+			NewResources:   node.Resources,
 		}
 		if err := tx.Create(&nodeedit).Error; err != nil {
 			return err
@@ -356,107 +783,707 @@ func (pg *PostgresDB) EditNode(ctx context.Context, user db.User, nodeID string,
 		return nil
 	})
 }
-func (pg *PostgresDB) AddEdgeWeightVote(ctx context.Context, user db.User, edgeID string, weight float64) error {
+func (pg *PostgresDB) RevertNode(ctx context.Context, user db.User, nodeID, editID string) error {
 	return pg.db.Transaction(func(tx *gorm.DB) error {
-		edgeedit := EdgeEdit{
-			EdgeID: atoi(edgeID),
-			UserID: atoi(user.Key),
-			Type:   db.EdgeEditTypeVote,
-			Weight: weight,
+		edit := NodeEdit{}
+		if err := tx.Where("id = ? AND node_id = ?", atoi(editID), atoi(nodeID)).First(&edit).Error; err != nil {
+			return errors.Wrapf(err, "failed to find edit '%s' of node '%s'", editID, nodeID)
 		}
-		if err := tx.Create(&edgeedit).Error; err != nil {
+		node := Node{Model: gorm.Model{ID: atoi(nodeID)}}
+		if err := tx.First(&node).Error; err != nil {
+			return err
+		}
+		node.Description = edit.NewDescription
+		// edits did not always record resources, keep the current ones then
+		if len(edit.NewResources) > 0 {
+			node.Resources = edit.NewResources
+		}
+		if err := tx.Save(&node).Error; err != nil {
+			return err
+		}
+		nodeedit := NodeEdit{
+			NodeID:         node.ID,
+			UserID:         atoi(user.Key),
+			Type:           db.NodeEditTypeRevert,
+			NewDescription: node.Description,
+			NewResources:   node.Resources,
+		}
+		return tx.Create(&nodeedit).Error
+	})
+}
+
+func (pg *PostgresDB) RemoveTranslation(ctx context.Context, user db.User, nodeID, language string, field model.TranslationField) error {
+	return pg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		node := Node{}
+		if err := tx.First(&node, atoi(nodeID)).Error; err != nil {
+			return errors.Wrapf(err, "no node with id='%s'", nodeID)
+		}
+		if err := removeTranslation(&node, language, field); err != nil {
+			return err
+		}
+		return saveTranslationChange(tx, user, node, db.NodeEditTypeRemoveTranslation)
+	})
+}
+
+func (pg *PostgresDB) MoveTranslation(ctx context.Context, user db.User, nodeID, fromLanguage, toLanguage string) error {
+	if _, ok := LanguageToLanguageFlag[toLanguage]; !ok {
+		return errors.Errorf("unsupported language '%s'", toLanguage)
+	}
+	return pg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		node := Node{}
+		if err := tx.First(&node, atoi(nodeID)).Error; err != nil {
+			return errors.Wrapf(err, "no node with id='%s'", nodeID)
+		}
+		if err := moveTranslation(&node, fromLanguage, toLanguage); err != nil {
+			return err
+		}
+		return saveTranslationChange(tx, user, node, db.NodeEditTypeMoveTranslation)
+	})
+}
+
+func (pg *PostgresDB) MoveTranslations(ctx context.Context, user db.User, filter db.TranslationFilter, fromLanguage, toLanguage string) ([]string, error) {
+	if _, ok := LanguageToLanguageFlag[toLanguage]; !ok {
+		return nil, errors.Errorf("unsupported language '%s'", toLanguage)
+	}
+	changed := []string{}
+	err := pg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		isAdmin, err := isUserAdmin(tx, user.Key)
+		if err != nil {
 			return err
 		}
-		{
-			// TODO(skep): should move aggregation to separate module/application
-			edge := Edge{Model: gorm.Model{ID: atoi(edgeID)}}
-			if err := tx.First(&edge).Error; err != nil {
+		if !isAdmin {
+			return errors.New("only admins may change translations in bulk")
+		}
+		query := filterTranslations(tx, filter, fromLanguage).
+			Where("(jsonb_exists(description, ?) OR jsonb_exists(resources, ?))", fromLanguage, fromLanguage).
+			Where("NOT jsonb_exists(description, ?) AND NOT coalesce(jsonb_exists(resources, ?), false)", toLanguage, toLanguage)
+		nodes := []Node{}
+		if err := query.Order("id").Find(&nodes).Error; err != nil {
+			return err
+		}
+		for _, node := range nodes {
+			if err := moveTranslation(&node, fromLanguage, toLanguage); err != nil {
+				return err
+			}
+			if err := saveTranslationChange(tx, user, node, db.NodeEditTypeMoveTranslation); err != nil {
 				return err
 			}
-			edits := []EdgeEdit{}
-			query := `
-            WITH RankedVotes AS (
-                SELECT *,
-                    -- Assign rank to each vote per user, most recent first
-                    ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY created_at DESC) as rownumber
-                FROM edge_edits
-                WHERE edge_id = ?
-            )
-            -- Select only the most recent vote for each user (i.e. rownumber 1)
-            SELECT * FROM RankedVotes WHERE rownumber = 1;
-            `
-			if err := tx.Raw(query, edge.ID).Scan(&edits).Error; err != nil {
+			changed = append(changed, itoa(node.ID))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to move translations")
+	}
+	return changed, nil
+}
+
+func (pg *PostgresDB) RemoveTranslations(ctx context.Context, user db.User, filter db.TranslationFilter, language string, field model.TranslationField) ([]string, error) {
+	changed := []string{}
+	err := pg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		isAdmin, err := isUserAdmin(tx, user.Key)
+		if err != nil {
+			return err
+		}
+		if !isAdmin {
+			return errors.New("only admins may change translations in bulk")
+		}
+		query := filterTranslations(tx, filter, language)
+		switch field {
+		case model.TranslationFieldDescription:
+			query = query.Where("jsonb_exists(description, ?)", language).
+				Where("(SELECT COUNT(*) FROM jsonb_object_keys(description)) > 1")
+		case model.TranslationFieldResources:
+			query = query.Where("coalesce(jsonb_exists(resources, ?), false)", language)
+		default:
+			return errors.Errorf("nodes have no field '%s'", field)
+		}
+		nodes := []Node{}
+		if err := query.Order("id").Find(&nodes).Error; err != nil {
+			return err
+		}
+		for _, node := range nodes {
+			if err := removeTranslation(&node, language, field); err != nil {
 				return err
 			}
-			sum := db.Sum(edits, func(edit EdgeEdit) float64 { return edit.Weight })
-			edge.Weight = sum / float64(len(edits))
-			if err := tx.Save(&edge).Error; err != nil {
+			if err := saveTranslationChange(tx, user, node, db.NodeEditTypeRemoveTranslation); err != nil {
 				return err
 			}
+			changed = append(changed, itoa(node.ID))
 		}
 		return nil
 	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to remove translations")
+	}
+	return changed, nil
 }
 
-// VerifyUserInput returns a CreateUserResult with an error message on
-// *invalid* user input, on valid user input nil is returned.
-func VerifyUserInput(ctx context.Context, user db.User, password string) *model.CreateUserResult {
-	if len(password) < MIN_PASSWORD_LENGTH {
-		msg := fmt.Sprintf("Password must be at least length %d, the provided one has only %d characters.", MIN_PASSWORD_LENGTH, len(password))
-		return &model.CreateUserResult{Login: &model.LoginResult{Success: false, Message: &msg}}
+// filterTranslations selects the nodes matching filter, the regular
+// expression is matched against the description in language.
+func filterTranslations(tx *gorm.DB, filter db.TranslationFilter, language string) *gorm.DB {
+	query := tx.Model(&Node{})
+	if len(filter.NodeIDs) > 0 {
+		query = query.Where("id IN ?", atoiAll(filter.NodeIDs))
 	}
-	if len(user.Username) < MIN_USERNAME_LENGTH {
-		msg := fmt.Sprintf("Username must be at least length %d, the provided one has only %d characters.", MIN_USERNAME_LENGTH, len(user.Username))
-		return &model.CreateUserResult{Login: &model.LoginResult{Success: false, Message: &msg}}
+	if filter.EditedBy != "" {
+		query = query.Where("id IN (?)", tx.Model(&NodeEdit{}).Select("node_id").Where("user_id = ?", atoi(filter.EditedBy)))
 	}
-	if _, err := mail.ParseAddress(user.EMail); err != nil {
-		msg := fmt.Sprintf("Invalid EMail: '%s'", user.EMail)
-		return &model.CreateUserResult{Login: &model.LoginResult{Success: false, Message: &msg}}
+	if filter.Matches != "" {
+		query = query.Where("description->>? ~ ?", language, filter.Matches)
+	}
+	return query
+}
+
+// removeTranslation removes the text of field in language from node.
+func removeTranslation(node *Node, language string, field model.TranslationField) error {
+	var text db.Text
+	switch field {
+	case model.TranslationFieldDescription:
+		text = node.Description
+		if len(text) == 1 {
+			return errors.New("cannot remove the only description of a node")
+		}
+	case model.TranslationFieldResources:
+		text = node.Resources
+	default:
+		return errors.Errorf("nodes have no field '%s'", field)
 	}
+	if _, ok := text[language]; !ok {
+		return errors.Errorf("node has no %s in language '%s'", field, language)
+	}
+	delete(text, language)
 	return nil
 }
 
-func (pg *PostgresDB) CreateUserWithEMail(ctx context.Context, username, password, email string) (*model.CreateUserResult, error) {
-	if res := VerifyUserInput(ctx, db.User{Username: username, EMail: email}, password); res != nil {
-		return res, nil
+// moveTranslation moves description and resources of node from language
+// `from` to language `to`.
+func moveTranslation(node *Node, from, to string) error {
+	if from == to {
+		return errors.New("cannot move a translation onto itself")
 	}
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to create password hash for user '%v', '%v'", username, email)
+	_, hasDescription := node.Description[from]
+	_, hasResources := node.Resources[from]
+	if !hasDescription && !hasResources {
+		return errors.Errorf("node has no translation in language '%s'", from)
 	}
-	user := User{
-		Username:     username,
-		EMail:        email,
-		PasswordHash: string(hash),
-		Tokens: []AuthenticationToken{
-			{
-				Token:  pg.newToken(),
-				Expiry: pg.timeNow().Add(AUTHENTICATION_TOKEN_EXPIRY),
-			},
-		},
+	_, toDescription := node.Description[to]
+	_, toResources := node.Resources[to]
+	if toDescription || toResources {
+		return errors.Errorf("node already has a translation in language '%s'", to)
 	}
-	if err := pg.db.Create(&user).Error; err != nil {
-		return nil, errors.Wrap(err, "failed to create user")
+	for _, text := range []db.Text{node.Description, node.Resources} {
+		if content, ok := text[from]; ok {
+			text[to] = content
+			delete(text, from)
+		}
 	}
-	return &model.CreateUserResult{Login: &model.LoginResult{
-		Success:  true,
-		Token:    user.Tokens[0].Token,
-		UserID:   itoa(user.ID),
-		UserName: user.Username,
-	}}, nil
+	return nil
 }
 
-func (pg *PostgresDB) Login(ctx context.Context, auth model.LoginAuthentication) (*model.LoginResult, error) {
-	user := User{EMail: auth.Email}
-	token := AuthenticationToken{Token: pg.newToken(), Expiry: pg.timeNow().Add(AUTHENTICATION_TOKEN_EXPIRY)}
-	passwordMissmatch := false
-	err := pg.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Where(&user).First(&user).Error; err != nil {
-			return err
-		}
-		if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(auth.Password)); err != nil {
-			passwordMissmatch = true
-			return nil
+// saveTranslationChange saves the texts of node and records the change in the
+// node's history.
+func saveTranslationChange(tx *gorm.DB, user db.User, node Node, editType db.NodeEditType) error {
+	if err := tx.Save(&node).Error; err != nil {
+		return err
+	}
+	return tx.Create(&NodeEdit{
+		NodeID:         node.ID,
+		UserID:         atoi(user.Key),
+		Type:           editType,
+		NewDescription: node.Description,
+		NewResources:   node.Resources,
+	}).Error
+}
+
+func (pg *PostgresDB) Tags(ctx context.Context) ([]*model.Tag, error) {
+	tags := []Tag{}
+	if err := pg.db.WithContext(ctx).Order("id").Find(&tags).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to query tags")
+	}
+	converter := newConvertToModelFromCtx(ctx)
+	result := make([]*model.Tag, 0, len(tags))
+	for _, tag := range tags {
+		result = append(result, converter.Tag(tag))
+	}
+	return result, nil
+}
+
+func (pg *PostgresDB) CreateTag(ctx context.Context, user db.User, name *model.Text) (string, error) {
+	tag := Tag{Name: db.ConvertToDBText(name)}
+	if len(tag.Name) == 0 {
+		return "", errors.New("tag name must not be empty")
+	}
+	if err := pg.db.WithContext(ctx).Create(&tag).Error; err != nil {
+		return "", errors.Wrap(err, "failed to create tag")
+	}
+	return itoa(tag.ID), nil
+}
+
+func (pg *PostgresDB) TagNode(ctx context.Context, user db.User, nodeID, tagID string) error {
+	return pg.changeNodeTag(ctx, user, nodeID, tagID, db.NodeEditTypeTag)
+}
+
+func (pg *PostgresDB) UntagNode(ctx context.Context, user db.User, nodeID, tagID string) error {
+	return pg.changeNodeTag(ctx, user, nodeID, tagID, db.NodeEditTypeUntag)
+}
+
+// changeNodeTag adds (NodeEditTypeTag) or removes (NodeEditTypeUntag) the tag
+// of a node and records the change in the nodes edit history.
+func (pg *PostgresDB) changeNodeTag(ctx context.Context, user db.User, nodeID, tagID string, editType db.NodeEditType) error {
+	return pg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		node := Node{}
+		if err := tx.First(&node, atoi(nodeID)).Error; err != nil {
+			return errors.Wrapf(err, "no node with id='%s'", nodeID)
+		}
+		tag := Tag{}
+		if err := tx.First(&tag, atoi(tagID)).Error; err != nil {
+			return errors.Wrapf(err, "no tag with id='%s'", tagID)
+		}
+		association := tx.Model(&node).Association("Tags")
+		var err error
+		if editType == db.NodeEditTypeTag {
+			err = association.Append(&tag)
+		} else {
+			err = association.Delete(&tag)
+		}
+		if err != nil {
+			return err
+		}
+		nodeedit := NodeEdit{
+			NodeID:         node.ID,
+			UserID:         atoi(user.Key),
+			Type:           editType,
+			NewDescription: node.Description,
+			NewResources:   node.Resources,
+			TagID:          &tag.ID,
+		}
+		return tx.Create(&nodeedit).Error
+	})
+}
+
+func (pg *PostgresDB) NodeResources(ctx context.Context, nodeIDs []string) (map[string][]*model.Resource, error) {
+	resources := []Resource{}
+	if err := pg.db.WithContext(ctx).Where("node_id IN ?", atoiAll(nodeIDs)).Order("votes DESC, id").Find(&resources).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to query resources")
+	}
+	converter := newConvertToModelFromCtx(ctx)
+	result := make(map[string][]*model.Resource, len(nodeIDs))
+	for _, resource := range resources {
+		nodeID := itoa(resource.NodeID)
+		result[nodeID] = append(result[nodeID], converter.Resource(resource))
+	}
+	return result, nil
+}
+
+func (pg *PostgresDB) ResourceEdits(ctx context.Context, resourceID string) ([]*model.ResourceEdit, error) {
+	edits := []ResourceEdit{}
+	if err := pg.db.WithContext(ctx).Where("resource_id = ?", atoi(resourceID)).Preload("User").Order("id").Find(&edits).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to query resource edits")
+	}
+	if len(edits) == 0 {
+		return nil, errors.Errorf("resource with id='%s' does not exist", resourceID)
+	}
+	return newConvertToModelFromCtx(ctx).ResourceEdits(edits), nil
+}
+
+func verifyResourceInput(input model.ResourceInput) error {
+	// only absolute web links, other schemes like javascript: would be
+	// executed by the clients linking to the resource
+	if u, err := url.Parse(input.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.Errorf("invalid resource URL '%s'", input.URL)
+	}
+	if input.Language == "" {
+		return errors.New("resource language must not be empty")
+	}
+	if input.DurationMinutes != nil && *input.DurationMinutes < 0 {
+		return errors.New("resource duration must not be negative")
+	}
+	return nil
+}
+
+func (pg *PostgresDB) CreateResource(ctx context.Context, user db.User, nodeID string, input model.ResourceInput) (string, error) {
+	if err := verifyResourceInput(input); err != nil {
+		return "", err
+	}
+	resource := Resource{
+		NodeID:          atoi(nodeID),
+		URL:             input.URL,
+		Title:           db.ConvertToDBText(input.Title),
+		Kind:            db.ResourceKind(input.Kind),
+		Language:        input.Language,
+		DurationMinutes: input.DurationMinutes,
+	}
+	err := pg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&Node{}, resource.NodeID).Error; err != nil {
+			return errors.Wrapf(err, "no node with id='%s'", nodeID)
+		}
+		if err := tx.Create(&resource).Error; err != nil {
+			return err
+		}
+		return tx.Create(newResourceEdit(user, resource, db.ResourceEditTypeCreate)).Error
+	})
+	return itoa(resource.ID), err
+}
+
+func (pg *PostgresDB) EditResource(ctx context.Context, user db.User, resourceID string, input model.ResourceInput) error {
+	if err := verifyResourceInput(input); err != nil {
+		return err
+	}
+	return pg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		resource := Resource{}
+		if err := tx.First(&resource, atoi(resourceID)).Error; err != nil {
+			return errors.Wrapf(err, "no resource with id='%s'", resourceID)
+		}
+		resource.URL = input.URL
+		resource.Title = mergeText(resource.Title, db.ConvertToDBText(input.Title))
+		resource.Kind = db.ResourceKind(input.Kind)
+		resource.Language = input.Language
+		resource.DurationMinutes = input.DurationMinutes
+		if err := tx.Save(&resource).Error; err != nil {
+			return err
+		}
+		return tx.Create(newResourceEdit(user, resource, db.ResourceEditTypeEdit)).Error
+	})
+}
+
+func (pg *PostgresDB) DeleteResource(ctx context.Context, user db.User, resourceID string) error {
+	return pg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var edits int64
+		if err := tx.Model(&ResourceEdit{}).Where("resource_id = ? AND user_id != ?", atoi(resourceID), user.Key).Count(&edits).Error; err != nil {
+			return err
+		}
+		isAdmin, err := isUserAdmin(tx, user.Key)
+		if err != nil {
+			return err
+		}
+		if edits >= 1 && !isAdmin {
+			return errors.New("resource has edits from other users, won't delete")
+		}
+		resource := Resource{}
+		if err := tx.First(&resource, atoi(resourceID)).Error; err != nil {
+			return errors.Wrapf(err, "no resource with id='%s'", resourceID)
+		}
+		if err := tx.Delete(&resource).Error; err != nil {
+			return err
+		}
+		return tx.Create(newResourceEdit(user, resource, db.ResourceEditTypeDelete)).Error
+	})
+}
+
+func newResourceEdit(user db.User, resource Resource, editType db.ResourceEditType) *ResourceEdit {
+	return &ResourceEdit{
+		ResourceID:      resource.ID,
+		UserID:          atoi(user.Key),
+		Type:            editType,
+		URL:             resource.URL,
+		Title:           resource.Title,
+		Kind:            resource.Kind,
+		Language:        resource.Language,
+		DurationMinutes: resource.DurationMinutes,
+	}
+}
+
+func (pg *PostgresDB) VoteResource(ctx context.Context, user db.User, resourceID string) error {
+	return pg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		resource := Resource{}
+		if err := tx.First(&resource, atoi(resourceID)).Error; err != nil {
+			return errors.Wrapf(err, "no resource with id='%s'", resourceID)
+		}
+		vote := ResourceVote{ResourceID: resource.ID, UserID: atoi(user.Key)}
+		// voting twice keeps the first vote
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&vote).Error; err != nil {
+			return err
+		}
+		var votes int64
+		if err := tx.Model(&ResourceVote{}).Where("resource_id = ?", resource.ID).Count(&votes).Error; err != nil {
+			return err
+		}
+		return tx.Model(&resource).Update("votes", votes).Error
+	})
+}
+
+func (pg *PostgresDB) AddEdgeWeightVote(ctx context.Context, user db.User, edgeID string, weight float64) error {
+	return pg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		edgeedit := EdgeEdit{
+			EdgeID: atoi(edgeID),
+			UserID: atoi(user.Key),
+			Type:   db.EdgeEditTypeVote,
+			Weight: weight,
+		}
+		if err := tx.Create(&edgeedit).Error; err != nil {
+			return err
+		}
+		// aggregation is deferred to AggregateDirtyEdges, so that concurrent
+		// votes do not serialize on scanning all votes of the edge
+		res := tx.Model(&Edge{}).Where("id = ?", atoi(edgeID)).Update("weight_dirty", true)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errors.Errorf("no edge with id='%s'", edgeID)
+		}
+		return nil
+	})
+}
+
+func (pg *PostgresDB) AggregateDirtyEdges(ctx context.Context, limit int) ([]string, error) {
+	ids := []string{}
+	err := pg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		edges := []Edge{}
+		// votes arriving during the aggregation wait for the row lock and mark
+		// the edge dirty again afterwards
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("weight_dirty").Order("id").Limit(limit).Find(&edges).Error
+		if err != nil {
+			return err
+		}
+		for i := range edges {
+			if err := pg.aggregateEdgeWeight(tx, &edges[i]); err != nil {
+				return errors.Wrapf(err, "edge with id='%d'", edges[i].ID)
+			}
+			ids = append(ids, itoa(edges[i].ID))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "transaction failed")
+	}
+	return ids, nil
+}
+
+// voteOrRetractEditTypes are the types of edge edits, that carry or withdraw
+// a weight vote.
+var voteOrRetractEditTypes = []db.EdgeEditType{db.EdgeEditTypeCreate, db.EdgeEditTypeVote, db.EdgeEditTypeRetract}
+
+func (pg *PostgresDB) RetractEdgeWeightVote(ctx context.Context, user db.User, edgeID string) error {
+	return pg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		latest := EdgeEdit{}
+		err := tx.Where("edge_id = ? AND user_id = ? AND type IN ?", atoi(edgeID), atoi(user.Key), voteOrRetractEditTypes).
+			Order("created_at DESC, id DESC").Limit(1).Find(&latest).Error
+		if err != nil {
+			return err
+		}
+		if latest.ID == 0 || latest.Type == db.EdgeEditTypeRetract {
+			return errors.Errorf("no vote of user '%s' on edge with id='%s'", user.Key, edgeID)
+		}
+		edgeedit := EdgeEdit{
+			EdgeID: atoi(edgeID),
+			UserID: atoi(user.Key),
+			Type:   db.EdgeEditTypeRetract,
+		}
+		if err := tx.Create(&edgeedit).Error; err != nil {
+			return err
+		}
+		res := tx.Model(&Edge{}).Where("id = ?", atoi(edgeID)).Update("weight_dirty", true)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errors.Errorf("no edge with id='%s'", edgeID)
+		}
+		return nil
+	})
+}
+
+func (pg *PostgresDB) EdgeVotes(ctx context.Context, user db.User) ([]*model.EdgeVote, error) {
+	edits := []EdgeEdit{}
+	query := `
+    SELECT * FROM (
+        SELECT DISTINCT ON (edge_edits.edge_id) edge_edits.*
+        FROM edge_edits JOIN edges ON edges.id = edge_edits.edge_id
+        WHERE edge_edits.user_id = ? AND edge_edits.type IN ? AND edges.deleted_at IS NULL
+        -- the most recent vote or retraction per edge
+        ORDER BY edge_edits.edge_id, edge_edits.created_at DESC, edge_edits.id DESC
+    ) AS latest WHERE type <> ?
+    ORDER BY edge_id;
+    `
+	if err := pg.db.WithContext(ctx).Raw(query, atoi(user.Key), voteOrRetractEditTypes, db.EdgeEditTypeRetract).Scan(&edits).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to query votes")
+	}
+	votes := make([]*model.EdgeVote, 0, len(edits))
+	for _, edit := range edits {
+		votes = append(votes, &model.EdgeVote{EdgeID: itoa(edit.EdgeID), Weight: edit.Weight, UpdatedAt: edit.CreatedAt})
+	}
+	return votes, nil
+}
+
+// aggregateEdgeWeight sets the weight of the edge to the aggregate of the most
+// recent vote of each user. Once all votes are retracted, the weight falls
+// back to the one the edge was created with.
+func (pg *PostgresDB) aggregateEdgeWeight(tx *gorm.DB, edge *Edge) error {
+	votes, err := latestVotes(tx, edge.ID)
+	if err != nil {
+		return err
+	}
+	edge.WeightDirty = false
+	if len(votes) > 0 {
+		edge.Weight = pg.aggregator.Aggregate(votes)
+	} else {
+		creation := EdgeEdit{}
+		err := tx.Where("edge_id = ? AND type = ?", edge.ID, db.EdgeEditTypeCreate).Order("id").Limit(1).Find(&creation).Error
+		if err != nil {
+			return err
+		}
+		if creation.ID != 0 {
+			edge.Weight = creation.Weight
+		}
+	}
+	return tx.Model(edge).Select("weight", "weight_dirty").Updates(edge).Error
+}
+
+// contributionEditTypes are the types of node edits, that count towards the
+// reputation of a user. Tagging, moving translations and the like take no
+// expertise.
+var contributionEditTypes = []db.NodeEditType{db.NodeEditTypeCreate, db.NodeEditTypeEdit}
+
+// latestVotes returns the most recent vote of each user on the edge, users
+// who retracted their most recent vote are left out.
+func latestVotes(tx *gorm.DB, edgeID uint) ([]aggregate.Vote, error) {
+	votes := []struct {
+		Weight    float64
+		CreatedAt time.Time
+		Edits     int
+	}{}
+	query := `
+    WITH RankedVotes AS (
+        SELECT *,
+            -- Assign rank to each vote per user, most recent first
+            ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY created_at DESC, id DESC) as rownumber
+        FROM edge_edits
+        WHERE edge_id = ? AND type IN ?
+    )
+    -- Select only the most recent vote for each user (i.e. rownumber 1),
+    -- together with the number of contributions of that user as their
+    -- reputation: the nodes they created or edited and the edges they
+    -- created. Nodes count once, otherwise editing repeatedly raises it.
+    SELECT weight, created_at,
+        (SELECT COUNT(DISTINCT node_id) FROM node_edits WHERE node_edits.user_id = RankedVotes.user_id AND node_edits.type IN ?)
+        + (SELECT COUNT(*) FROM edge_edits WHERE edge_edits.user_id = RankedVotes.user_id AND edge_edits.type = ?) AS edits
+    FROM RankedVotes WHERE rownumber = 1 AND type <> ?;
+    `
+	if err := tx.Raw(query, edgeID, voteOrRetractEditTypes, contributionEditTypes, db.EdgeEditTypeCreate, db.EdgeEditTypeRetract).Scan(&votes).Error; err != nil {
+		return nil, err
+	}
+	aggregateVotes := make([]aggregate.Vote, 0, len(votes))
+	for _, vote := range votes {
+		aggregateVotes = append(aggregateVotes, aggregate.Vote{
+			Weight:     vote.Weight,
+			Time:       vote.CreatedAt,
+			Reputation: aggregate.ReputationOf(vote.Edits),
+		})
+	}
+	return aggregateVotes, nil
+}
+
+// voteHistogramBucketWidth is the range of weights counted together in the
+// histogram of EdgeVoteStats.
+const voteHistogramBucketWidth = 1.0
+
+func (pg *PostgresDB) EdgeVoteStats(ctx context.Context, edgeID string) (*model.EdgeVoteStats, error) {
+	tx := pg.db.WithContext(ctx)
+	edge := Edge{}
+	if err := tx.First(&edge, atoi(edgeID)).Error; err != nil {
+		return nil, errors.Wrapf(err, "no edge with id='%s'", edgeID)
+	}
+	votes, err := latestVotes(tx, edge.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query votes")
+	}
+	stats := &model.EdgeVoteStats{Count: len(votes), Histogram: []*model.VoteBucket{}}
+	if len(votes) == 0 {
+		return stats, nil
+	}
+	mean, median, stdDev := aggregate.Mean{}.Aggregate(votes), aggregate.Median{}.Aggregate(votes), aggregate.StdDev(votes)
+	stats.Mean, stats.Median, stats.StdDev = &mean, &median, &stdDev
+	for _, bucket := range aggregate.Histogram(votes, voteHistogramBucketWidth) {
+		stats.Histogram = append(stats.Histogram, &model.VoteBucket{From: bucket.From, To: bucket.To, Count: bucket.Count})
+	}
+	return stats, nil
+}
+
+// RecomputeEdgeWeights aggregates the weight of every edge anew, which is
+// required after the vote aggregation strategy changed. Returns the number of
+// edges whose weight changed.
+func (pg *PostgresDB) RecomputeEdgeWeights(ctx context.Context, batchSize int) (int, error) {
+	changed := 0
+	edges := []Edge{}
+	err := pg.db.WithContext(ctx).Order("id").FindInBatches(&edges, batchSize, func(batch *gorm.DB, _ int) error {
+		return pg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			for i := range edges {
+				before := edges[i].Weight
+				if err := pg.aggregateEdgeWeight(tx, &edges[i]); err != nil {
+					return errors.Wrapf(err, "edge with id='%d'", edges[i].ID)
+				}
+				if edges[i].Weight != before {
+					changed++
+				}
+			}
+			return nil
+		})
+	}).Error
+	return changed, err
+}
+
+// VerifyUserInput returns a CreateUserResult with an error message on
+// *invalid* user input, on valid user input nil is returned.
+func VerifyUserInput(ctx context.Context, user db.User, password string) *model.CreateUserResult {
+	if len(password) < MIN_PASSWORD_LENGTH {
+		msg := fmt.Sprintf("Password must be at least length %d, the provided one has only %d characters.", MIN_PASSWORD_LENGTH, len(password))
+		return &model.CreateUserResult{Login: &model.LoginResult{Success: false, Message: &msg}}
+	}
+	if len(user.Username) < MIN_USERNAME_LENGTH {
+		msg := fmt.Sprintf("Username must be at least length %d, the provided one has only %d characters.", MIN_USERNAME_LENGTH, len(user.Username))
+		return &model.CreateUserResult{Login: &model.LoginResult{Success: false, Message: &msg}}
+	}
+	if _, err := mail.ParseAddress(user.EMail); err != nil {
+		msg := fmt.Sprintf("Invalid EMail: '%s'", user.EMail)
+		return &model.CreateUserResult{Login: &model.LoginResult{Success: false, Message: &msg}}
+	}
+	return nil
+}
+
+func (pg *PostgresDB) CreateUserWithEMail(ctx context.Context, username, password, email string) (*model.CreateUserResult, error) {
+	if res := VerifyUserInput(ctx, db.User{Username: username, EMail: email}, password); res != nil {
+		return res, nil
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create password hash for user '%v', '%v'", username, email)
+	}
+	user := User{
+		Username:     username,
+		EMail:        email,
+		PasswordHash: string(hash),
+		Tokens: []AuthenticationToken{
+			{
+				Token:  pg.newToken(),
+				Expiry: pg.timeNow().Add(AUTHENTICATION_TOKEN_EXPIRY),
+			},
+		},
+	}
+	if err := pg.db.Create(&user).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to create user")
+	}
+	return &model.CreateUserResult{Login: &model.LoginResult{
+		Success:  true,
+		Token:    user.Tokens[0].Token,
+		UserID:   itoa(user.ID),
+		UserName: user.Username,
+	}}, nil
+}
+
+func (pg *PostgresDB) Login(ctx context.Context, auth model.LoginAuthentication) (*model.LoginResult, error) {
+	user := User{EMail: auth.Email}
+	token := AuthenticationToken{Token: pg.newToken(), Expiry: pg.timeNow().Add(AUTHENTICATION_TOKEN_EXPIRY)}
+	passwordMissmatch := false
+	err := pg.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where(&user).First(&user).Error; err != nil {
+			return err
+		}
+		if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(auth.Password)); err != nil {
+			passwordMissmatch = true
+			return nil
 		}
 		token.UserID = user.ID
 		if err := tx.Create(&token).Error; err != nil {
@@ -530,9 +1557,14 @@ func (pg *PostgresDB) DeleteNode(ctx context.Context, user db.User, ID string) e
 		if edges >= 1 {
 			return errors.New("cannot delete node with edges, remove edges first")
 		}
-		if err := tx.Delete(&Node{Model: gorm.Model{ID: atoi(ID)}}).Error; err != nil {
+		node := Node{}
+		if err := tx.First(&node, atoi(ID)).Error; err != nil {
 			return err
 		}
+		if err := tx.Delete(&node).Error; err != nil {
+			return err
+		}
+		ownEdges := []Edge{}
 		if err := tx.
 			Where(`
                 edges.id IN (
@@ -541,16 +1573,215 @@ func (pg *PostgresDB) DeleteNode(ctx context.Context, user db.User, ID string) e
                     WHERE (edges.from_id = ? OR edges.to_id = ?) AND edge_edits.user_id = ?
                 )
             `, ID, ID, user.Key).
-			Delete(&Edge{}).Error; err != nil {
+			Find(&ownEdges).Error; err != nil {
 			return err
 		}
-		return tx.Where("node_id = ?", ID).Delete(&NodeEdit{}).Error
+		for _, edge := range ownEdges {
+			if err := softDeleteEdge(tx, user, edge); err != nil {
+				return err
+			}
+		}
+		nodeedit := NodeEdit{
+			NodeID:         node.ID,
+			UserID:         atoi(user.Key),
+			Type:           db.NodeEditTypeDelete,
+			NewDescription: node.Description,
+			NewResources:   node.Resources,
+		}
+		return tx.Create(&nodeedit).Error
 	}); err != nil {
 		return errors.Wrap(err, "transaction failed")
 	}
 	return nil
 }
 
+// softDeleteEdge moves the edge into the trash and records the deletion in
+// its edit history.
+func softDeleteEdge(tx *gorm.DB, user db.User, edge Edge) error {
+	if err := tx.Delete(&edge).Error; err != nil {
+		return err
+	}
+	edgeedit := EdgeEdit{
+		EdgeID:   edge.ID,
+		UserID:   atoi(user.Key),
+		Type:     db.EdgeEditTypeDelete,
+		Weight:   edge.Weight,
+		EdgeType: edge.Type,
+	}
+	return tx.Create(&edgeedit).Error
+}
+
+func (pg *PostgresDB) ResolveNodeIDs(ctx context.Context, IDs []string) ([]string, error) {
+	return resolveIDs(pg.db.WithContext(ctx), &NodeRedirect{}, IDs)
+}
+
+func (pg *PostgresDB) ResolveEdgeIDs(ctx context.Context, IDs []string) ([]string, error) {
+	return resolveIDs(pg.db.WithContext(ctx), &EdgeRedirect{}, IDs)
+}
+
+// resolveIDs follows the redirects in the table of redirectModel, redirects
+// never point to another redirect.
+func resolveIDs(tx *gorm.DB, redirectModel interface{}, IDs []string) ([]string, error) {
+	ids := make([]uint, 0, len(IDs))
+	for _, id := range IDs {
+		ids = append(ids, atoi(id))
+	}
+	redirects := []struct{ FromID, ToID uint }{}
+	if err := tx.Model(redirectModel).Select("from_id, to_id").Where("from_id IN ?", ids).Scan(&redirects).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to query redirects")
+	}
+	to := make(map[uint]uint, len(redirects))
+	for _, redirect := range redirects {
+		to[redirect.FromID] = redirect.ToID
+	}
+	resolved := make([]string, 0, len(IDs))
+	for i, id := range ids {
+		if toID, ok := to[id]; ok {
+			resolved = append(resolved, itoa(toID))
+		} else {
+			resolved = append(resolved, IDs[i])
+		}
+	}
+	return resolved, nil
+}
+
+func (pg *PostgresDB) MergeNodes(ctx context.Context, user db.User, keepID, removeID string) error {
+	if keepID == removeID {
+		return errors.New("cannot merge a node into itself")
+	}
+	if err := pg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		allowed, err := hasAnyRole(tx, user.Key, db.RoleAdmin, db.RoleModerator)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return errors.New("only admins and moderators may merge nodes")
+		}
+		keep, remove := Node{}, Node{}
+		if err := tx.Preload("Tags").First(&keep, atoi(keepID)).Error; err != nil {
+			return errors.Wrapf(err, "no node with id='%s'", keepID)
+		}
+		if err := tx.Preload("Tags").First(&remove, atoi(removeID)).Error; err != nil {
+			return errors.Wrapf(err, "no node with id='%s'", removeID)
+		}
+		repointed, err := pg.mergeEdges(tx, keep.ID, remove.ID)
+		if err != nil {
+			return err
+		}
+		// the graph was acyclic before, so any new cycle uses a re-pointed edge
+		for _, edge := range repointed {
+			if err := checkEdgeCreatesNoCycle(tx, edge); err != nil {
+				return err
+			}
+		}
+		// translations of the kept node win
+		keep.Description = mergeText(remove.Description, keep.Description)
+		keep.Resources = mergeText(remove.Resources, keep.Resources)
+		if err := tx.Omit("Tags").Save(&keep).Error; err != nil {
+			return err
+		}
+		if len(remove.Tags) > 0 {
+			if err := tx.Model(&keep).Association("Tags").Append(remove.Tags); err != nil {
+				return err
+			}
+		}
+		for _, stmt := range []string{
+			`UPDATE node_edits SET node_id = ? WHERE node_id = ?`,
+			`UPDATE resources SET node_id = ? WHERE node_id = ?`,
+			`UPDATE node_redirects SET to_id = ? WHERE to_id = ?`,
+		} {
+			if err := tx.Exec(stmt, keep.ID, remove.ID).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(&NodeRedirect{FromID: remove.ID, ToID: keep.ID}).Error; err != nil {
+			return err
+		}
+		nodeedit := NodeEdit{
+			NodeID:         keep.ID,
+			UserID:         atoi(user.Key),
+			Type:           db.NodeEditTypeMerge,
+			NewDescription: keep.Description,
+			NewResources:   keep.Resources,
+		}
+		if err := tx.Create(&nodeedit).Error; err != nil {
+			return err
+		}
+		// everything worth keeping was moved to the kept node
+		return tx.Unscoped().Delete(&remove).Error
+	}); err != nil {
+		return errors.Wrap(err, "transaction failed")
+	}
+	return nil
+}
+
+// mergeEdges re-points all edges of node `remove` to node `keep`. Edges that
+// would duplicate an edge of `keep` are removed, and their votes are added to
+// that edge instead, their IDs redirect to that edge. Deleted edges are
+// re-pointed as they are, restoring them fails while they duplicate an edge.
+// Edges between both nodes are removed. Returns the re-pointed edges.
+func (pg *PostgresDB) mergeEdges(tx *gorm.DB, keep, remove uint) ([]Edge, error) {
+	edges := []Edge{}
+	if err := tx.Unscoped().Where("from_id = ? OR to_id = ?", remove, remove).Order("id").Find(&edges).Error; err != nil {
+		return nil, err
+	}
+	repointed := []Edge{}
+	for _, edge := range edges {
+		if edge.FromID == keep || edge.ToID == keep {
+			if err := tx.Unscoped().Delete(&edge).Error; err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if edge.FromID == remove {
+			edge.FromID = keep
+		} else {
+			edge.ToID = keep
+		}
+		duplicate := Edge{}
+		if !edge.DeletedAt.Valid {
+			if err := tx.Where("from_id = ? AND to_id = ?", edge.FromID, edge.ToID).Limit(1).Find(&duplicate).Error; err != nil {
+				return nil, err
+			}
+		}
+		if duplicate.ID == 0 {
+			if err := tx.Unscoped().Model(&edge).Updates(map[string]interface{}{"from_id": edge.FromID, "to_id": edge.ToID}).Error; err != nil {
+				return nil, err
+			}
+			if !edge.DeletedAt.Valid {
+				repointed = append(repointed, edge)
+			}
+			continue
+		}
+		for _, stmt := range []string{
+			`UPDATE edge_edits SET edge_id = ? WHERE edge_id = ?`,
+			`UPDATE edge_redirects SET to_id = ? WHERE to_id = ?`,
+		} {
+			if err := tx.Exec(stmt, duplicate.ID, edge.ID).Error; err != nil {
+				return nil, err
+			}
+		}
+		if err := tx.Create(&EdgeRedirect{FromID: edge.ID, ToID: duplicate.ID}).Error; err != nil {
+			return nil, err
+		}
+		if err := tx.Unscoped().Delete(&edge).Error; err != nil {
+			return nil, err
+		}
+		if err := pg.aggregateEdgeWeight(tx, &duplicate); err != nil {
+			return nil, err
+		}
+	}
+	return repointed, nil
+}
+
+func hasAnyRole(tx *gorm.DB, userID string, roles ...db.RoleType) (bool, error) {
+	var count int64
+	if err := tx.Model(&Role{}).Where("user_id = ? AND role IN ?", userID, roles).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count >= 1, nil
+}
+
 func isUserAdmin(tx *gorm.DB, userID string) (bool, error) {
 	var roleAdmin int64
 	if err := tx.Model(&Role{}).Where("user_id = ? AND role = ?", userID, db.RoleAdmin).Count(&roleAdmin).Error; err != nil {
@@ -574,16 +1805,195 @@ func (pg *PostgresDB) DeleteEdge(ctx context.Context, user db.User, ID string) e
 		if edits >= 1 && !isAdmin {
 			return errors.New("edge has edits from other users, won't delete")
 		}
-		if err := tx.Unscoped().Delete(&Edge{Model: gorm.Model{ID: atoi(ID)}}).Error; err != nil {
+		edge := Edge{}
+		if err := tx.First(&edge, atoi(ID)).Error; err != nil {
 			return err
 		}
-		return tx.Where("edge_id = ?", ID).Delete(&EdgeEdit{}).Error
+		return softDeleteEdge(tx, user, edge)
 	}); err != nil {
 		return errors.Wrap(err, "transaction failed")
 	}
 	return nil
 }
 
+var errAdminOnly = errors.New("only admins may access the trash")
+
+func (pg *PostgresDB) DeletedNodes(ctx context.Context, user db.User) ([]*model.DeletedNode, error) {
+	nodes := []Node{}
+	deletedBy := map[uint]string{}
+	err := pg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		isAdmin, err := isUserAdmin(tx, user.Key)
+		if err != nil {
+			return err
+		}
+		if !isAdmin {
+			return errAdminOnly
+		}
+		if err := tx.Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&nodes).Error; err != nil {
+			return err
+		}
+		edits := []NodeEdit{}
+		if err := tx.Where("type = ? AND node_id IN (SELECT id FROM nodes WHERE deleted_at IS NOT NULL)", db.NodeEditTypeDelete).Preload("User").Order("id").Find(&edits).Error; err != nil {
+			return err
+		}
+		// the most recent deletion wins for nodes deleted more than once
+		for _, edit := range edits {
+			deletedBy[edit.NodeID] = edit.User.Username
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query deleted nodes")
+	}
+	converter := newConvertToModelFromCtx(ctx)
+	result := make([]*model.DeletedNode, 0, len(nodes))
+	for _, node := range nodes {
+		result = append(result, &model.DeletedNode{
+			Node:      converter.Node(node),
+			DeletedAt: node.DeletedAt.Time,
+			DeletedBy: optionalString(deletedBy[node.ID]),
+		})
+	}
+	return result, nil
+}
+
+func (pg *PostgresDB) DeletedEdges(ctx context.Context, user db.User) ([]*model.DeletedEdge, error) {
+	edges := []Edge{}
+	deletedBy := map[uint]string{}
+	err := pg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		isAdmin, err := isUserAdmin(tx, user.Key)
+		if err != nil {
+			return err
+		}
+		if !isAdmin {
+			return errAdminOnly
+		}
+		if err := tx.Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&edges).Error; err != nil {
+			return err
+		}
+		edits := []EdgeEdit{}
+		if err := tx.Where("type = ? AND edge_id IN (SELECT id FROM edges WHERE deleted_at IS NOT NULL)", db.EdgeEditTypeDelete).Preload("User").Order("id").Find(&edits).Error; err != nil {
+			return err
+		}
+		for _, edit := range edits {
+			deletedBy[edit.EdgeID] = edit.User.Username
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query deleted edges")
+	}
+	converter := newConvertToModelFromCtx(ctx)
+	result := make([]*model.DeletedEdge, 0, len(edges))
+	for _, edge := range edges {
+		result = append(result, &model.DeletedEdge{
+			Edge:      converter.Edge(edge),
+			DeletedAt: edge.DeletedAt.Time,
+			DeletedBy: optionalString(deletedBy[edge.ID]),
+		})
+	}
+	return result, nil
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func (pg *PostgresDB) RestoreNode(ctx context.Context, user db.User, ID string) error {
+	if err := pg.db.Transaction(func(tx *gorm.DB) error {
+		isAdmin, err := isUserAdmin(tx, user.Key)
+		if err != nil {
+			return err
+		}
+		if !isAdmin {
+			return errAdminOnly
+		}
+		node := Node{}
+		if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&node, atoi(ID)).Error; err != nil {
+			return errors.Wrapf(err, "no deleted node with id='%s'", ID)
+		}
+		if err := tx.Unscoped().Model(&node).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		nodeedit := NodeEdit{
+			NodeID:         node.ID,
+			UserID:         atoi(user.Key),
+			Type:           db.NodeEditTypeRestore,
+			NewDescription: node.Description,
+			NewResources:   node.Resources,
+		}
+		return tx.Create(&nodeedit).Error
+	}); err != nil {
+		return errors.Wrap(err, "transaction failed")
+	}
+	return nil
+}
+
+func (pg *PostgresDB) RestoreEdge(ctx context.Context, user db.User, ID string) error {
+	if err := pg.db.Transaction(func(tx *gorm.DB) error {
+		isAdmin, err := isUserAdmin(tx, user.Key)
+		if err != nil {
+			return err
+		}
+		if !isAdmin {
+			return errAdminOnly
+		}
+		edge := Edge{}
+		if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&edge, atoi(ID)).Error; err != nil {
+			return errors.Wrapf(err, "no deleted edge with id='%s'", ID)
+		}
+		var activeNodes int64
+		if err := tx.Model(&Node{}).Where("id IN ?", []uint{edge.FromID, edge.ToID}).Count(&activeNodes).Error; err != nil {
+			return err
+		}
+		if activeNodes != 2 {
+			return errors.New("cannot restore edge to a deleted node, restore the node first")
+		}
+		// e.g. after merging the nodes of a deleted edge, or creating the edge anew
+		duplicate := Edge{}
+		if err := tx.Where("from_id = ? AND to_id = ?", edge.FromID, edge.ToID).Limit(1).Find(&duplicate).Error; err != nil {
+			return err
+		}
+		if duplicate.ID != 0 {
+			return errors.Errorf("cannot restore edge, it duplicates the edge with id='%d'", duplicate.ID)
+		}
+		if err := checkEdgeCreatesNoCycle(tx, edge); err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&edge).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		edgeedit := EdgeEdit{
+			EdgeID:   edge.ID,
+			UserID:   atoi(user.Key),
+			Type:     db.EdgeEditTypeRestore,
+			Weight:   edge.Weight,
+			EdgeType: edge.Type,
+		}
+		return tx.Create(&edgeedit).Error
+	}); err != nil {
+		return errors.Wrap(err, "transaction failed")
+	}
+	return nil
+}
+
+func (pg *PostgresDB) PurgeTrash(ctx context.Context, olderThan time.Time) error {
+	// edits, and edges of purged nodes are removed by their OnDelete:CASCADE
+	// constraints
+	if err := pg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("deleted_at < ?", olderThan).Delete(&Edge{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("deleted_at < ?", olderThan).Delete(&Node{}).Error
+	}); err != nil {
+		return errors.Wrap(err, "failed to purge trash")
+	}
+	return nil
+}
+
 func (pg *PostgresDB) Logout(ctx context.Context) error {
 	token := middleware.CtxGetAuthentication(ctx)
 	user := User{Model: gorm.Model{ID: atoi(middleware.CtxGetUserID(ctx))}}
@@ -626,15 +2036,45 @@ func (pg *PostgresDB) DeleteAccount(ctx context.Context) error {
 
 func (pg *PostgresDB) NodeEdits(ctx context.Context, ID string) ([]*model.NodeEdit, error) {
 	edits := []NodeEdit{}
-	err := pg.db.Where("node_id = ?", ID).Preload("User").Find(&edits).Error
+	err := pg.db.Where("node_id = ?", atoi(ID)).Preload("User").Preload("Tag").Find(&edits).Error
 	if len(edits) == 0 {
 		return nil, errors.Errorf("nodeedit for node.id='%s' does not exist", ID)
 	}
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to query edits")
 	}
-	lang := middleware.CtxGetLanguage(ctx)
-	return NewConvertToModel(lang).NodeEdits(edits), nil
+	return newConvertToModelFromCtx(ctx).NodeEdits(edits), nil
+}
+
+func (pg *PostgresDB) NodeEditsByID(ctx context.Context, nodeID string, editIDs []string) ([]db.NodeEdit, error) {
+	ids := make([]uint, 0, len(editIDs))
+	for _, id := range editIDs {
+		ids = append(ids, atoi(id))
+	}
+	edits := []NodeEdit{}
+	if err := pg.db.WithContext(ctx).Where("node_id = ? AND id IN ?", atoi(nodeID), ids).Find(&edits).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to query edits")
+	}
+	byID := make(map[uint]NodeEdit, len(edits))
+	for _, edit := range edits {
+		byID[edit.ID] = edit
+	}
+	result := make([]db.NodeEdit, 0, len(ids))
+	for i, id := range ids {
+		edit, ok := byID[id]
+		if !ok {
+			return nil, errors.Errorf("nodeedit id='%s' of node.id='%s' does not exist", editIDs[i], nodeID)
+		}
+		result = append(result, db.NodeEdit{
+			Document:  db.Document{Key: itoa(edit.ID)},
+			Node:      itoa(edit.NodeID),
+			User:      itoa(edit.UserID),
+			Type:      edit.Type,
+			NewNode:   db.Node{Document: db.Document{Key: itoa(edit.NodeID)}, Description: edit.NewDescription, Resources: edit.NewResources},
+			CreatedAt: edit.CreatedAt.Unix(),
+		})
+	}
+	return result, nil
 }
 
 func (pg *PostgresDB) EdgeEdits(ctx context.Context, ID string) ([]*model.EdgeEdit, error) {
@@ -645,28 +2085,209 @@ func (pg *PostgresDB) EdgeEdits(ctx context.Context, ID string) ([]*model.EdgeEd
 	if len(edits) == 0 {
 		return nil, errors.Errorf("edge with id='%s' does not exist", ID)
 	}
-	lang := middleware.CtxGetLanguage(ctx)
-	return NewConvertToModel(lang).EdgeEdits(edits), nil
+	return newConvertToModelFromCtx(ctx).EdgeEdits(edits), nil
+}
+
+// Search snippets mark the matched words with control characters, since the
+// text is user input and must be escaped before the words are enclosed in
+// <b></b>, see snippetToHTML.
+const (
+	headlineStartSel = "\x02"
+	headlineStopSel  = "\x03"
+	headlineOptions  = `StartSel="` + headlineStartSel + `", StopSel="` + headlineStopSel + `"`
+)
+
+// snippetToHTML escapes the snippet and encloses its matched words in <b></b>.
+func snippetToHTML(snippet string) string {
+	return strings.NewReplacer(headlineStartSel, "<b>", headlineStopSel, "</b>").Replace(html.EscapeString(snippet))
+}
+
+func (pg *PostgresDB) Search(ctx context.Context, query, language string, limit, offset int) ([]*model.SearchHit, error) {
+	// regional variants are searched in the column of their primary language
+	language, _, _ = strings.Cut(language, "-")
+	config, ok := textSearchConfigs[language]
+	if !ok {
+		return nil, errors.Errorf("full-text search is not available for language '%s'", language)
+	}
+	hits := []struct {
+		Node               `gorm:"embedded"`
+		Rank               float64
+		DescriptionSnippet string
+		ResourcesSnippet   string
+	}{}
+	column := searchColumn(language)
+	err := pg.db.WithContext(ctx).Raw(fmt.Sprintf(`
+		SELECT nodes.*, ts_rank(%[1]s, q) AS rank,
+			ts_headline('%[2]s', coalesce(description->>'%[3]s', ''), q, ?) AS description_snippet,
+			ts_headline('%[2]s', coalesce(resources->>'%[3]s', ''), q, ?) AS resources_snippet
+		FROM nodes, websearch_to_tsquery('%[2]s', ?) q
+		WHERE deleted_at IS NULL AND %[1]s @@ q
+		ORDER BY rank DESC, id
+		LIMIT ? OFFSET ?`, column, config, language), headlineOptions, headlineOptions, query, limit, offset).
+		Scan(&hits).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "full-text search failed")
+	}
+	ids := []uint{}
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	tags := []struct {
+		Tag    `gorm:"embedded"`
+		NodeID uint
+	}{}
+	err = pg.db.WithContext(ctx).Table("tags").
+		Select("tags.*, node_tags.node_id").
+		Joins("JOIN node_tags ON node_tags.tag_id = tags.id").
+		Where("node_tags.node_id IN ? AND tags.deleted_at IS NULL", ids).
+		Order("tags.id").
+		Scan(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	tagsByNodeID := map[uint][]Tag{}
+	for _, tag := range tags {
+		tagsByNodeID[tag.NodeID] = append(tagsByNodeID[tag.NodeID], tag.Tag)
+	}
+	converter := NewConvertToModel(language)
+	result := []*model.SearchHit{}
+	for _, hit := range hits {
+		hit.Node.Tags = tagsByNodeID[hit.ID]
+		searchHit := &model.SearchHit{
+			Node:               converter.Node(hit.Node),
+			Rank:               hit.Rank,
+			DescriptionSnippet: snippetToHTML(hit.DescriptionSnippet),
+		}
+		if hit.ResourcesSnippet != "" {
+			resourcesSnippet := snippetToHTML(hit.ResourcesSnippet)
+			searchHit.ResourcesSnippet = &resourcesSnippet
+		}
+		result = append(result, searchHit)
+	}
+	return result, nil
+}
+
+func (pg *PostgresDB) TranslationCoverage(ctx context.Context) ([]*model.TranslationCoverage, error) {
+	languages := supportedLanguages()
+	counts := []struct {
+		Lang                           string
+		Total, Descriptions, Resources int
+	}{}
+	err := pg.db.WithContext(ctx).Raw(`
+		SELECT lang, COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE jsonb_exists(description, lang)) AS descriptions,
+			COUNT(*) FILTER (WHERE jsonb_exists(resources, lang)) AS resources
+		FROM nodes CROSS JOIN unnest(string_to_array(?, ',')) AS lang
+		WHERE deleted_at IS NULL
+		GROUP BY lang`, strings.Join(languages, ",")).
+		Scan(&counts).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count translations")
+	}
+	coverageByLanguage := map[string]*model.TranslationCoverage{}
+	for _, count := range counts {
+		coverageByLanguage[count.Lang] = &model.TranslationCoverage{
+			Language:     count.Lang,
+			Descriptions: count.Descriptions,
+			Resources:    count.Resources,
+			Total:        count.Total,
+		}
+	}
+	result := []*model.TranslationCoverage{}
+	for _, lang := range languages {
+		coverage, ok := coverageByLanguage[lang]
+		if !ok { // no nodes at all
+			coverage = &model.TranslationCoverage{Language: lang}
+		}
+		result = append(result, coverage)
+	}
+	return result, nil
 }
 
-func (pg *PostgresDB) NodeMatchFuzzy(ctx context.Context, substring string) ([]*model.Node, error) {
+func (pg *PostgresDB) UntranslatedNodes(ctx context.Context, language string, first int, after string) ([]*model.Node, error) {
+	if _, ok := LanguageToLanguageFlag[language]; !ok {
+		return nil, errors.Errorf("unsupported language '%s'", language)
+	}
 	nodes := []Node{}
-	limit := 50 // TODO: adjust the limit
-	substring = strings.ToLower(substring)
-	err := pg.db.WithContext(ctx).
-		Select("*, similarity(description->>'en', ?) as sim", substring). // 'similarity' is pg_trgm operator
-		Where("(description->>'en') % ?", substring).                     // % is the similarity operator of pg_trgm
-		Order("sim DESC").
-		Limit(limit).
+	err := pg.db.WithContext(ctx).Preload("Tags").
+		Where("NOT jsonb_exists(description, ?) AND id > ?", language, atoi(after)).
+		Order("id").
+		Limit(first).
 		Find(&nodes).Error
 	if err != nil {
 		return nil, err
 	}
-	var result []*model.Node
-	converter := NewConvertToModel(middleware.CtxGetLanguage(ctx))
-	for _, n := range nodes {
-		result = append(result, converter.Node(n))
+	converter := newConvertToModelFromCtx(ctx)
+	result := []*model.Node{}
+	for _, node := range nodes {
+		result = append(result, converter.Node(node))
 	}
+	return result, nil
+}
 
+// NodeMatchFuzzy searches the languages of the request's fallback chain first,
+// then all other supported languages in sorted order, each result records the
+// language it matched in.
+func (pg *PostgresDB) NodeMatchFuzzy(ctx context.Context, substring string, tagIDs []string) ([]*model.Node, error) {
+	limit := 50 // TODO: adjust the limit
+	substring = strings.ToLower(substring)
+	ordered := []string{}
+	seen := map[string]bool{}
+	candidates := append([]string{}, middleware.CtxGetLanguages(ctx)...)
+	candidates = append(append(candidates, defaultFallbackLanguage), supportedLanguages()...)
+	for _, lang := range candidates {
+		if _, ok := LanguageToLanguageFlag[lang]; ok && !seen[lang] {
+			seen[lang] = true
+			ordered = append(ordered, lang)
+		}
+	}
+	// a single query over all languages, each node matches in the first
+	// language of ordered, in which it is similar
+	matchesPerLanguage, args := []string{}, []interface{}{}
+	for priority, lang := range ordered {
+		match := fmt.Sprintf(`SELECT id, '%s' AS matched_language, %d AS priority, similarity(%s, ?) AS sim
+			FROM nodes WHERE deleted_at IS NULL AND %s %% ?`, lang, priority, descriptionOf(lang), descriptionOf(lang)) // % is the similarity operator of pg_trgm
+		args = append(args, substring, substring)
+		if len(tagIDs) > 0 {
+			match += " AND id IN (SELECT node_id FROM node_tags WHERE tag_id IN ?)"
+			args = append(args, atoiAll(tagIDs))
+		}
+		matchesPerLanguage = append(matchesPerLanguage, match)
+	}
+	matches := []struct {
+		ID              uint
+		MatchedLanguage string
+	}{}
+	err := pg.db.WithContext(ctx).Raw(`
+		SELECT id, matched_language FROM (
+			SELECT DISTINCT ON (id) * FROM (`+strings.Join(matchesPerLanguage, " UNION ALL ")+`) matches
+			ORDER BY id, priority, sim DESC
+		) best
+		ORDER BY priority, sim DESC, id
+		LIMIT ?`, append(args, limit)...).
+		Scan(&matches).Error
+	if err != nil {
+		return nil, err
+	}
+	ids := []uint{}
+	for _, match := range matches {
+		ids = append(ids, match.ID)
+	}
+	nodes := []Node{}
+	if err := pg.db.WithContext(ctx).Where("id IN ?", ids).Find(&nodes).Error; err != nil {
+		return nil, err
+	}
+	nodeByID := map[uint]Node{}
+	for _, node := range nodes {
+		nodeByID[node.ID] = node
+	}
+	result := []*model.Node{}
+	converter := newConvertToModelFromCtx(ctx)
+	for _, match := range matches {
+		matched := match.MatchedLanguage
+		node := converter.Node(nodeByID[match.ID])
+		node.MatchedLanguage = &matched
+		result = append(result, node)
+	}
 	return result, nil
 }
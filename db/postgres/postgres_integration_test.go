@@ -4,6 +4,7 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/suxatcode/learn-graph-poc-backend/db"
+	"github.com/suxatcode/learn-graph-poc-backend/db/aggregate"
 	"github.com/suxatcode/learn-graph-poc-backend/graph/model"
 	"github.com/suxatcode/learn-graph-poc-backend/middleware"
 	"gorm.io/gorm"
@@ -153,7 +155,7 @@ func TestPostgresDB_CreateNode(t *testing.T) {
 			assert.NoError(pg.db.Create(&user).Error)
 			description := model.Text{Translations: test.Description}
 			resources := model.Text{Translations: test.Resources}
-			id, err := pg.CreateNode(ctx, db.User{Document: db.Document{Key: itoa(user.ID)}}, &description, &resources)
+			id, err := pg.CreateNode(ctx, db.User{Document: db.Document{Key: itoa(user.ID)}}, &description, &resources, false)
 			if !assert.NoError(err) {
 				return
 			}
@@ -229,6 +231,133 @@ func TestPostgresDB_EditNode(t *testing.T) {
 			assert.NoError(pg.db.Find(&editnodes).Error)
 			assert.Len(editnodes, 1)
 			assert.Equal(db.NodeEditTypeEdit, editnodes[0].Type)
+			assert.Equal(test.ExpDescription, editnodes[0].NewDescription)
+			assert.Equal(test.ExpResources, editnodes[0].NewResources)
+		})
+	}
+}
+
+func TestPostgresDB_RevertNode(t *testing.T) {
+	for _, test := range []struct {
+		Name           string
+		NodeID, EditID string
+		ExpError       bool
+		ExpDescription db.Text
+		ExpResources   db.Text
+	}{
+		{
+			Name:           "revert to creation",
+			NodeID:         "1",
+			EditID:         "1",
+			ExpDescription: db.Text{"en": "A"},
+			ExpResources:   db.Text{"en": "R"},
+		},
+		{
+			Name:           "revert to edit without resources keeps resources",
+			NodeID:         "1",
+			EditID:         "2",
+			ExpDescription: db.Text{"en": "B"},
+			ExpResources:   db.Text{"en": "RR"},
+		},
+		{
+			Name:     "edit of other node",
+			NodeID:   "1",
+			EditID:   "4",
+			ExpError: true,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			pg := setupDB(t)
+			ctx := context.Background()
+			assert := assert.New(t)
+			user := User{Model: gorm.Model{ID: 1}, Username: "123", PasswordHash: "000", EMail: "a@b"}
+			assert.NoError(pg.db.Create(&user).Error)
+			for _, node := range []Node{
+				{Model: gorm.Model{ID: 1}, Description: db.Text{"en": "C"}, Resources: db.Text{"en": "RR"}},
+				{Model: gorm.Model{ID: 2}, Description: db.Text{"en": "X"}},
+			} {
+				assert.NoError(pg.db.Create(&node).Error)
+			}
+			// IDs are assigned in order of creation: 1, 2, 3, 4
+			for _, edit := range []NodeEdit{
+				{NodeID: 1, UserID: 1, Type: db.NodeEditTypeCreate, NewDescription: db.Text{"en": "A"}, NewResources: db.Text{"en": "R"}},
+				{NodeID: 1, UserID: 1, Type: db.NodeEditTypeEdit, NewDescription: db.Text{"en": "B"}},
+				{NodeID: 1, UserID: 1, Type: db.NodeEditTypeEdit, NewDescription: db.Text{"en": "C"}, NewResources: db.Text{"en": "RR"}},
+				{NodeID: 2, UserID: 1, Type: db.NodeEditTypeCreate, NewDescription: db.Text{"en": "X"}},
+			} {
+				assert.NoError(pg.db.Create(&edit).Error)
+			}
+			err := pg.RevertNode(ctx, db.User{Document: db.Document{Key: "1"}}, test.NodeID, test.EditID)
+			if test.ExpError {
+				assert.Error(err)
+				return
+			}
+			if !assert.NoError(err) {
+				return
+			}
+			node := Node{}
+			assert.NoError(pg.db.First(&node, 1).Error)
+			assert.Equal(test.ExpDescription, node.Description)
+			assert.Equal(test.ExpResources, node.Resources)
+			edit := NodeEdit{}
+			assert.NoError(pg.db.Order("id DESC").First(&edit).Error)
+			assert.Equal(db.NodeEditTypeRevert, edit.Type)
+			assert.Equal(test.ExpDescription, edit.NewDescription)
+		})
+	}
+}
+
+func TestPostgresDB_NodeEditsByID(t *testing.T) {
+	for _, test := range []struct {
+		Name            string
+		EditIDs         []string
+		ExpError        bool
+		ExpDescriptions []db.Text
+	}{
+		{
+			Name:            "edits in requested order",
+			EditIDs:         []string{"2", "1"},
+			ExpDescriptions: []db.Text{{"en": "B", "de": "b"}, {"en": "A"}},
+		},
+		{
+			Name:     "edit of other node",
+			EditIDs:  []string{"1", "3"},
+			ExpError: true,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			pg := setupDB(t)
+			ctx := context.Background()
+			assert := assert.New(t)
+			user := User{Model: gorm.Model{ID: 1}, Username: "123", PasswordHash: "000", EMail: "a@b"}
+			assert.NoError(pg.db.Create(&user).Error)
+			for _, node := range []Node{
+				{Model: gorm.Model{ID: 1}, Description: db.Text{"en": "B", "de": "b"}},
+				{Model: gorm.Model{ID: 2}, Description: db.Text{"en": "X"}},
+			} {
+				assert.NoError(pg.db.Create(&node).Error)
+			}
+			// IDs are assigned in order of creation: 1, 2, 3
+			for _, edit := range []NodeEdit{
+				{NodeID: 1, UserID: 1, Type: db.NodeEditTypeCreate, NewDescription: db.Text{"en": "A"}},
+				{NodeID: 1, UserID: 1, Type: db.NodeEditTypeEdit, NewDescription: db.Text{"en": "B", "de": "b"}},
+				{NodeID: 2, UserID: 1, Type: db.NodeEditTypeCreate, NewDescription: db.Text{"en": "X"}},
+			} {
+				assert.NoError(pg.db.Create(&edit).Error)
+			}
+			edits, err := pg.NodeEditsByID(ctx, "1", test.EditIDs)
+			if test.ExpError {
+				assert.Error(err)
+				return
+			}
+			if !assert.NoError(err) {
+				return
+			}
+			descriptions := []db.Text{}
+			for _, edit := range edits {
+				descriptions = append(descriptions, edit.NewNode.Description)
+			}
+			assert.Equal(test.ExpDescriptions, descriptions)
 		})
 	}
 }
@@ -261,7 +390,7 @@ func TestPostgresDB_CreateEdge(t *testing.T) {
 			user := User{Username: "123", PasswordHash: "000", EMail: "a@b"}
 			assert.NoError(pg.db.Create(&user).Error)
 			// call it
-			id, err := pg.CreateEdge(ctx, db.User{Document: db.Document{Key: itoa(user.ID)}}, fmt.Sprint(A.ID), fmt.Sprint(B.ID), 3.141)
+			id, err := pg.CreateEdge(ctx, db.User{Document: db.Document{Key: itoa(user.ID)}}, fmt.Sprint(A.ID), fmt.Sprint(B.ID), 3.141, db.EdgeTypePartOf)
 			if test.EdgeExists {
 				assert.Error(err)
 				return
@@ -276,13 +405,135 @@ func TestPostgresDB_CreateEdge(t *testing.T) {
 			assert.Equal(3.141, edges[0].Weight)
 			assert.Equal(A.ID, edges[0].FromID)
 			assert.Equal(B.ID, edges[0].ToID)
+			assert.Equal(db.EdgeTypePartOf, edges[0].Type)
 			edgeedits := []EdgeEdit{}
 			assert.NoError(pg.db.Find(&edgeedits).Error)
 			assert.Len(edgeedits, 1)
+			assert.Equal(db.EdgeTypePartOf, edgeedits[0].EdgeType)
+		})
+	}
+}
+
+func TestPostgresDB_CreateEdge_Cycles(t *testing.T) {
+	for _, test := range []struct {
+		Name          string
+		From, To      uint
+		EdgeType      db.EdgeType
+		ExistingEdges []Edge
+		ExpectedCycle []string
+	}{
+		{
+			Name:          "self loop",
+			From:          1,
+			To:            1,
+			ExpectedCycle: []string{"1", "1"},
+		},
+		{
+			Name:          "direct cycle",
+			From:          2,
+			To:            1,
+			ExistingEdges: []Edge{{FromID: 1, ToID: 2}},
+			ExpectedCycle: []string{"2", "1", "2"},
+		},
+		{
+			Name: "transitive cycle",
+			From: 3,
+			To:   1,
+			ExistingEdges: []Edge{
+				{FromID: 1, ToID: 2},
+				{FromID: 2, ToID: 3},
+			},
+			ExpectedCycle: []string{"3", "1", "2", "3"},
+		},
+		{
+			Name:          "related edges may form cycles",
+			From:          2,
+			To:            1,
+			EdgeType:      db.EdgeTypeRelated,
+			ExistingEdges: []Edge{{FromID: 1, ToID: 2}},
+		},
+		{
+			Name:          "cycles of other edge types are ignored",
+			From:          2,
+			To:            1,
+			ExistingEdges: []Edge{{FromID: 1, ToID: 2, Type: db.EdgeTypePartOf}},
+		},
+		{
+			Name: "no cycle, reverse direction",
+			From: 1,
+			To:   3,
+			ExistingEdges: []Edge{
+				{FromID: 1, ToID: 2},
+				{FromID: 2, ToID: 3},
+			},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			pg := setupDB(t)
+			ctx := context.Background()
+			assert := assert.New(t)
+			for _, id := range []uint{1, 2, 3} {
+				assert.NoError(pg.db.Create(&Node{Model: gorm.Model{ID: id}, Description: db.Text{"en": itoa(id)}}).Error)
+			}
+			for _, edge := range test.ExistingEdges {
+				assert.NoError(pg.db.Create(&edge).Error)
+			}
+			user := User{Username: "123", PasswordHash: "000", EMail: "a@b"}
+			assert.NoError(pg.db.Create(&user).Error)
+			edgeType := db.EdgeTypePrerequisite
+			if test.EdgeType != "" {
+				edgeType = test.EdgeType
+			}
+			_, err := pg.CreateEdge(ctx, db.User{Document: db.Document{Key: itoa(user.ID)}}, itoa(test.From), itoa(test.To), 5, edgeType)
+			if test.ExpectedCycle == nil {
+				assert.NoError(err)
+				return
+			}
+			cycleErr := &db.CycleError{}
+			if assert.ErrorAs(err, &cycleErr) {
+				assert.Equal(test.ExpectedCycle, cycleErr.Cycle)
+			}
+			var edges int64
+			assert.NoError(pg.db.Model(&Edge{}).Count(&edges).Error)
+			assert.Equal(int64(len(test.ExistingEdges)), edges)
 		})
 	}
 }
 
+func TestPostgresDB_CreateEdge_ConcurrentCycle(t *testing.T) {
+	pg := setupDB(t)
+	ctx := context.Background()
+	assert := assert.New(t)
+	for _, id := range []uint{1, 2} {
+		assert.NoError(pg.db.Create(&Node{Model: gorm.Model{ID: id}, Description: db.Text{"en": itoa(id)}}).Error)
+	}
+	user := User{Username: "123", PasswordHash: "000", EMail: "a@b"}
+	assert.NoError(pg.db.Create(&user).Error)
+	dbUser := db.User{Document: db.Document{Key: itoa(user.ID)}}
+	for i := 0; i < 10; i++ {
+		assert.NoError(pg.db.Unscoped().Where("true").Delete(&EdgeEdit{}).Error)
+		assert.NoError(pg.db.Unscoped().Where("true").Delete(&Edge{}).Error)
+		errs := make(chan error, 2)
+		for _, edge := range [][2]string{{"1", "2"}, {"2", "1"}} {
+			go func(from, to string) {
+				_, err := pg.CreateEdge(ctx, dbUser, from, to, 5, db.EdgeTypePrerequisite)
+				errs <- err
+			}(edge[0], edge[1])
+		}
+		cycleErrs := 0
+		for j := 0; j < 2; j++ {
+			err := <-errs
+			cycleErr := &db.CycleError{}
+			if errors.As(err, &cycleErr) {
+				cycleErrs++
+			} else {
+				assert.NoError(err)
+			}
+		}
+		assert.Equal(1, cycleErrs, "exactly one of the edges closes the cycle")
+	}
+}
+
 func TestPostgresDB_AddEdgeWeightVote(t *testing.T) {
 	for _, test := range []struct {
 		Name                 string
@@ -391,12 +642,177 @@ func TestPostgresDB_AddEdgeWeightVote(t *testing.T) {
 			}
 			assert.Len(edgeedits, test.ExpectedEdgeEdits)
 			edge := Edge{}
-			assert.NoError(pg.db.First(&edge).Error)
+			assert.NoError(pg.db.First(&edge, test.TargetEdgeID).Error)
+			assert.True(edge.WeightDirty, "aggregation is deferred")
+			ids, err := pg.AggregateDirtyEdges(ctx, 10)
+			assert.NoError(err)
+			assert.Equal([]string{itoa(test.TargetEdgeID)}, ids)
+			assert.NoError(pg.db.First(&edge, test.TargetEdgeID).Error)
 			assert.Equal(test.ExpectedWeight, edge.Weight)
+			assert.False(edge.WeightDirty)
 		})
 	}
 }
 
+func TestPostgresDB_AddEdgeWeightVote_noEdge(t *testing.T) {
+	pg := setupDB(t)
+	assert := assert.New(t)
+	user := User{Model: gorm.Model{ID: 111}, Username: "asdf", PasswordHash: "000", EMail: "a@b"}
+	assert.NoError(pg.db.Create(&user).Error)
+	err := pg.AddEdgeWeightVote(context.Background(), db.User{Document: db.Document{Key: itoa(111)}}, "88", 4)
+	assert.Error(err)
+	count := int64(0)
+	assert.NoError(pg.db.Model(&EdgeEdit{}).Count(&count).Error)
+	assert.Equal(int64(0), count)
+}
+
+func TestPostgresDB_AggregateDirtyEdges(t *testing.T) {
+	pg := setupDB(t)
+	ctx := context.Background()
+	assert := assert.New(t)
+	user := User{Model: gorm.Model{ID: 111}, Username: "asdf", PasswordHash: "000", EMail: "a@b"}
+	assert.NoError(pg.db.Create(&user).Error)
+	for _, node := range []Node{
+		{Model: gorm.Model{ID: 1}, Description: db.Text{"en": "A"}},
+		{Model: gorm.Model{ID: 2}, Description: db.Text{"en": "B"}},
+		{Model: gorm.Model{ID: 3}, Description: db.Text{"en": "C"}},
+	} {
+		assert.NoError(pg.db.Create(&node).Error)
+	}
+	for _, edge := range []Edge{
+		{Model: gorm.Model{ID: 1}, FromID: 1, ToID: 2, Weight: 1},
+		{Model: gorm.Model{ID: 2}, FromID: 2, ToID: 3, Weight: 1},
+		{Model: gorm.Model{ID: 3}, FromID: 1, ToID: 3, Weight: 1},
+	} {
+		assert.NoError(pg.db.Create(&edge).Error)
+	}
+	voter := db.User{Document: db.Document{Key: itoa(111)}}
+	assert.NoError(pg.AddEdgeWeightVote(ctx, voter, "1", 3))
+	assert.NoError(pg.AddEdgeWeightVote(ctx, voter, "3", 5))
+
+	ids, err := pg.AggregateDirtyEdges(ctx, 1)
+	assert.NoError(err)
+	assert.Equal([]string{"1"}, ids)
+	ids, err = pg.AggregateDirtyEdges(ctx, 1)
+	assert.NoError(err)
+	assert.Equal([]string{"3"}, ids)
+	ids, err = pg.AggregateDirtyEdges(ctx, 1)
+	assert.NoError(err)
+	assert.Empty(ids)
+	edges := []Edge{}
+	assert.NoError(pg.db.Order("id").Find(&edges).Error)
+	if assert.Len(edges, 3) {
+		assert.Equal(3.0, edges[0].Weight)
+		assert.Equal(1.0, edges[1].Weight, "unaffected by votes on other edges")
+		assert.Equal(5.0, edges[2].Weight)
+	}
+
+	// edges locked by a concurrent aggregation are skipped
+	assert.NoError(pg.AddEdgeWeightVote(ctx, voter, "2", 4))
+	tx := pg.db.Begin()
+	assert.NoError(tx.Exec("SELECT * FROM edges WHERE id = 2 FOR UPDATE").Error)
+	ids, err = pg.AggregateDirtyEdges(ctx, 10)
+	assert.NoError(err)
+	assert.Empty(ids)
+	assert.NoError(tx.Rollback().Error)
+	ids, err = pg.AggregateDirtyEdges(ctx, 10)
+	assert.NoError(err)
+	assert.Equal([]string{"2"}, ids)
+}
+
+func TestPostgresDB_RecomputeEdgeWeights(t *testing.T) {
+	pg := setupDB(t)
+	ctx := context.Background()
+	assert := assert.New(t)
+	for _, user := range []User{
+		{Model: gorm.Model{ID: 111}, Username: "asdf", PasswordHash: "000", EMail: "a@b"},
+		{Model: gorm.Model{ID: 222}, Username: "fasd", PasswordHash: "111", EMail: "c@d"},
+		{Model: gorm.Model{ID: 333}, Username: "dfas", PasswordHash: "222", EMail: "e@f"},
+	} {
+		assert.NoError(pg.db.Create(&user).Error)
+	}
+	for _, node := range []Node{
+		{Model: gorm.Model{ID: 1}, Description: db.Text{"en": "A"}},
+		{Model: gorm.Model{ID: 2}, Description: db.Text{"en": "B"}},
+	} {
+		assert.NoError(pg.db.Create(&node).Error)
+	}
+	for _, edge := range []Edge{
+		{Model: gorm.Model{ID: 88}, FromID: 1, ToID: 2, Weight: 4},
+		{Model: gorm.Model{ID: 99}, FromID: 2, ToID: 1, Weight: 5},
+	} {
+		assert.NoError(pg.db.Create(&edge).Error)
+	}
+	for _, edit := range []EdgeEdit{
+		{EdgeID: 88, UserID: 111, Weight: 2, Type: db.EdgeEditTypeCreate},
+		{EdgeID: 88, UserID: 222, Weight: 3, Type: db.EdgeEditTypeVote},
+		{EdgeID: 88, UserID: 333, Weight: 7, Type: db.EdgeEditTypeVote},
+		{EdgeID: 99, UserID: 111, Weight: 5, Type: db.EdgeEditTypeCreate},
+	} {
+		assert.NoError(pg.db.Create(&edit).Error)
+	}
+	pg.aggregator = aggregate.Median{}
+	changed, err := pg.RecomputeEdgeWeights(ctx, 1)
+	assert.NoError(err)
+	assert.Equal(1, changed)
+	edges := []Edge{}
+	assert.NoError(pg.db.Order("id").Find(&edges).Error)
+	if assert.Len(edges, 2) {
+		assert.Equal(3.0, edges[0].Weight)
+		assert.Equal(5.0, edges[1].Weight)
+	}
+}
+
+func TestPostgresDB_latestVotes_reputation(t *testing.T) {
+	pg := setupDB(t)
+	assert := assert.New(t)
+	for _, user := range []User{
+		{Model: gorm.Model{ID: 111}, Username: "asdf", PasswordHash: "000", EMail: "a@b"},
+		{Model: gorm.Model{ID: 222}, Username: "fasd", PasswordHash: "111", EMail: "c@d"},
+	} {
+		assert.NoError(pg.db.Create(&user).Error)
+	}
+	for _, node := range []Node{
+		{Model: gorm.Model{ID: 1}, Description: db.Text{"en": "A"}},
+		{Model: gorm.Model{ID: 2}, Description: db.Text{"en": "B"}},
+	} {
+		assert.NoError(pg.db.Create(&node).Error)
+	}
+	assert.NoError(pg.db.Create(&Edge{Model: gorm.Model{ID: 88}, FromID: 1, ToID: 2, Weight: 4}).Error)
+	assert.NoError(pg.db.Create(&Edge{Model: gorm.Model{ID: 99}, FromID: 2, ToID: 1, Weight: 5}).Error)
+	assert.NoError(pg.db.Create(&EdgeEdit{EdgeID: 88, UserID: 111, Weight: 4, Type: db.EdgeEditTypeCreate}).Error)
+	assert.NoError(pg.db.Create(&EdgeEdit{EdgeID: 88, UserID: 222, Weight: 2, Type: db.EdgeEditTypeVote}).Error)
+	reputationOf := func(userWeight float64) float64 {
+		votes, err := latestVotes(pg.db, 88)
+		assert.NoError(err)
+		for _, vote := range votes {
+			if vote.Weight == userWeight {
+				return vote.Reputation
+			}
+		}
+		t.Fatalf("no vote with weight %v in %v", userWeight, votes)
+		return 0
+	}
+	assert.Equal(aggregate.ReputationOf(1), reputationOf(4), "creating the edge counts")
+	assert.Equal(aggregate.ReputationOf(0), reputationOf(2))
+	for i := 0; i < 10; i++ {
+		assert.NoError(pg.db.Create(&EdgeEdit{EdgeID: 88, UserID: 222, Weight: 2, Type: db.EdgeEditTypeVote}).Error)
+		assert.NoError(pg.db.Create(&EdgeEdit{EdgeID: 99, UserID: 222, Weight: 2, Type: db.EdgeEditTypeVote}).Error)
+	}
+	assert.Equal(aggregate.ReputationOf(0), reputationOf(2), "voting repeatedly must not raise the weight of the voter")
+	for i := 0; i < 10; i++ {
+		assert.NoError(pg.db.Create(&NodeEdit{NodeID: 1, UserID: 222, Type: db.NodeEditTypeTag}).Error)
+		assert.NoError(pg.db.Create(&NodeEdit{NodeID: 1, UserID: 222, Type: db.NodeEditTypeUntag}).Error)
+	}
+	assert.Equal(aggregate.ReputationOf(0), reputationOf(2), "toggling tags must not raise the weight of the voter")
+	for i := 0; i < 10; i++ {
+		assert.NoError(pg.db.Create(&NodeEdit{NodeID: 1, UserID: 222, Type: db.NodeEditTypeEdit}).Error)
+	}
+	assert.Equal(aggregate.ReputationOf(1), reputationOf(2), "editing a node counts once")
+	assert.NoError(pg.db.Create(&NodeEdit{NodeID: 2, UserID: 222, Type: db.NodeEditTypeCreate}).Error)
+	assert.Equal(aggregate.ReputationOf(2), reputationOf(2), "creating a node counts")
+}
+
 func TestPostgresDB_CreateUserWithEMail(t *testing.T) {
 	for _, test := range []struct {
 		Name, Username, Password, EMail string
@@ -458,6 +874,7 @@ func TestPostgresDB_Graph(t *testing.T) {
 		Name     string
 		Nodes    []Node
 		Edges    []Edge
+		Filter   db.GraphFilter
 		ExpGraph *model.Graph
 	}{
 		{
@@ -472,12 +889,33 @@ func TestPostgresDB_Graph(t *testing.T) {
 			},
 			ExpGraph: &model.Graph{
 				Nodes: []*model.Node{
-					{ID: "1", Description: "A"},
-					{ID: "2", Description: "B"},
+					{ID: "1", Description: "A", Translations: translationsOf(db.Text{"en": "A"}, nil)},
+					{ID: "2", Description: "B", Translations: translationsOf(db.Text{"en": "B"}, nil)},
+				},
+				Edges: []*model.Edge{
+					{ID: "3", From: "1", To: "2", Weight: 5.0, Type: model.EdgeTypePrerequisite},
+					{ID: "4", From: "2", To: "1", Weight: 6.0, Type: model.EdgeTypePrerequisite},
+				},
+			},
+		},
+		{
+			Name: "filter by edge type",
+			Nodes: []Node{
+				{Model: gorm.Model{ID: 1}, Description: db.Text{"en": "A"}},
+				{Model: gorm.Model{ID: 2}, Description: db.Text{"en": "B"}},
+			},
+			Edges: []Edge{
+				{Model: gorm.Model{ID: 3}, FromID: 1, ToID: 2, Weight: 5.0, Type: db.EdgeTypeRelated},
+				{Model: gorm.Model{ID: 4}, FromID: 2, ToID: 1, Weight: 6.0, Type: db.EdgeTypePrerequisite},
+			},
+			Filter: db.GraphFilter{EdgeTypes: []db.EdgeType{db.EdgeTypeRelated, db.EdgeTypePartOf}},
+			ExpGraph: &model.Graph{
+				Nodes: []*model.Node{
+					{ID: "1", Description: "A", Translations: translationsOf(db.Text{"en": "A"}, nil)},
+					{ID: "2", Description: "B", Translations: translationsOf(db.Text{"en": "B"}, nil)},
 				},
 				Edges: []*model.Edge{
-					{ID: "3", From: "1", To: "2", Weight: 5.0},
-					{ID: "4", From: "2", To: "1", Weight: 6.0},
+					{ID: "3", From: "1", To: "2", Weight: 5.0, Type: model.EdgeTypeRelated},
 				},
 			},
 		},
@@ -492,17 +930,126 @@ func TestPostgresDB_Graph(t *testing.T) {
 			for _, edge := range test.Edges {
 				assert.NoError(pg.db.Create(&edge).Error)
 			}
-			graph, err := pg.Graph(ctx)
+			graph, err := pg.Graph(ctx, test.Filter)
 			assert.NoError(err)
 			assert.Equal(test.ExpGraph, graph)
 		})
 	}
 }
 
+func TestPostgresDB_Subgraph(t *testing.T) {
+	// 1 -> 2 -> 3 -> 4, 5 -> 2
+	nodes := []Node{}
+	for _, id := range []uint{1, 2, 3, 4, 5} {
+		nodes = append(nodes, Node{Model: gorm.Model{ID: id}, Description: db.Text{"en": itoa(id)}})
+	}
+	edges := []Edge{
+		{Model: gorm.Model{ID: 11}, FromID: 1, ToID: 2},
+		{Model: gorm.Model{ID: 12}, FromID: 2, ToID: 3},
+		{Model: gorm.Model{ID: 13}, FromID: 3, ToID: 4},
+		{Model: gorm.Model{ID: 14}, FromID: 5, ToID: 2},
+	}
+	for _, test := range []struct {
+		Name        string
+		Center      string
+		Depth       int
+		Direction   model.Direction
+		ExpectNodes []string
+		ExpectEdges []string
+		ExpectErr   bool
+	}{
+		{
+			Name:        "depth 0",
+			Center:      "2",
+			Depth:       0,
+			Direction:   model.DirectionBoth,
+			ExpectNodes: []string{"2"},
+			ExpectEdges: []string{},
+		},
+		{
+			Name:        "out, depth 1",
+			Center:      "2",
+			Depth:       1,
+			Direction:   model.DirectionOut,
+			ExpectNodes: []string{"2", "3"},
+			ExpectEdges: []string{"12"},
+		},
+		{
+			Name:        "in, depth 1",
+			Center:      "2",
+			Depth:       1,
+			Direction:   model.DirectionIn,
+			ExpectNodes: []string{"1", "2", "5"},
+			ExpectEdges: []string{"11", "14"},
+		},
+		{
+			Name:        "both, depth 2",
+			Center:      "2",
+			Depth:       2,
+			Direction:   model.DirectionBoth,
+			ExpectNodes: []string{"1", "2", "3", "4", "5"},
+			ExpectEdges: []string{"11", "12", "13", "14"},
+		},
+		{
+			Name:      "center does not exist",
+			Center:    "99",
+			Depth:     1,
+			Direction: model.DirectionBoth,
+			ExpectErr: true,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			pg := setupDB(t)
+			ctx := context.Background()
+			assert := assert.New(t)
+			assert.NoError(pg.db.Create(&nodes).Error)
+			assert.NoError(pg.db.Create(&edges).Error)
+			graph, err := pg.Subgraph(ctx, test.Center, test.Depth, test.Direction)
+			if test.ExpectErr {
+				assert.Error(err)
+				return
+			}
+			if !assert.NoError(err) {
+				return
+			}
+			nodeIDs, edgeIDs := []string{}, []string{}
+			for _, node := range graph.Nodes {
+				nodeIDs = append(nodeIDs, node.ID)
+			}
+			for _, edge := range graph.Edges {
+				edgeIDs = append(edgeIDs, edge.ID)
+			}
+			assert.Equal(test.ExpectNodes, nodeIDs)
+			assert.Equal(test.ExpectEdges, edgeIDs)
+		})
+	}
+}
+
+func TestPostgresDB_GraphOfNodes(t *testing.T) {
+	pg := setupDB(t)
+	ctx := context.Background()
+	assert := assert.New(t)
+	for _, id := range []uint{1, 2, 3} {
+		assert.NoError(pg.db.Create(&Node{Model: gorm.Model{ID: id}, Description: db.Text{"en": itoa(id)}}).Error)
+	}
+	assert.NoError(pg.db.Create(&Edge{Model: gorm.Model{ID: 11}, FromID: 1, ToID: 2}).Error)
+	assert.NoError(pg.db.Create(&Edge{Model: gorm.Model{ID: 12}, FromID: 2, ToID: 3}).Error)
+	graph, err := pg.GraphOfNodes(ctx, []string{"1", "2"})
+	assert.NoError(err)
+	assert.Equal(&model.Graph{
+		Nodes: []*model.Node{
+			{ID: "1", Description: "1", Translations: translationsOf(db.Text{"en": "1"}, nil)},
+			{ID: "2", Description: "2", Translations: translationsOf(db.Text{"en": "2"}, nil)},
+		},
+		Edges: []*model.Edge{{ID: "11", From: "1", To: "2", Type: model.EdgeTypePrerequisite}},
+	}, graph)
+}
+
 func TestPostgresDB_Node(t *testing.T) {
 	for _, test := range []struct {
 		Name    string
 		Nodes   []Node
+		NodeID  string
 		ExpNode *model.Node
 	}{
 		{
@@ -510,14 +1057,23 @@ func TestPostgresDB_Node(t *testing.T) {
 			Nodes: []Node{
 				{Model: gorm.Model{ID: 1}, Description: db.Text{"en": "A"}},
 			},
-			ExpNode: &model.Node{ID: "1", Description: "A"},
+			ExpNode: &model.Node{ID: "1", Description: "A", Translations: translationsOf(db.Text{"en": "A"}, nil)},
 		},
 		{
 			Name: "description & resources",
 			Nodes: []Node{
 				{Model: gorm.Model{ID: 1}, Description: db.Text{"en": "A"}, Resources: db.Text{"en": "B"}},
 			},
-			ExpNode: &model.Node{ID: "1", Description: "A", Resources: strptr("B")},
+			ExpNode: &model.Node{ID: "1", Description: "A", Resources: strptr("B"), Translations: translationsOf(db.Text{"en": "A"}, db.Text{"en": "B"})},
+		},
+		{
+			Name: "multiple nodes",
+			Nodes: []Node{
+				{Model: gorm.Model{ID: 1}, Description: db.Text{"en": "A"}},
+				{Model: gorm.Model{ID: 2}, Description: db.Text{"en": "B"}},
+			},
+			NodeID:  "2",
+			ExpNode: &model.Node{ID: "2", Description: "B", Translations: translationsOf(db.Text{"en": "B"}, nil)},
 		},
 	} {
 		t.Run(test.Name, func(t *testing.T) {
@@ -527,7 +1083,10 @@ func TestPostgresDB_Node(t *testing.T) {
 			for _, node := range test.Nodes {
 				assert.NoError(pg.db.Create(&node).Error)
 			}
-			node, err := pg.Node(ctx, "1")
+			if test.NodeID == "" {
+				test.NodeID = "1"
+			}
+			node, err := pg.Node(ctx, test.NodeID)
 			assert.NoError(err)
 			assert.Equal(test.ExpNode, node)
 		})
@@ -681,6 +1240,7 @@ func TestPostgresDB_DeleteNode(t *testing.T) {
 		PreexistingEdges     []Edge
 		PreexistingEdgeEdits []EdgeEdit
 		ExpLenNodeEdits      int
+		ExpLenEdgeEdits      int
 	}{
 		{
 			Name:           "sucess: no edges, no edits",
@@ -695,7 +1255,8 @@ func TestPostgresDB_DeleteNode(t *testing.T) {
 				{NodeID: 2, UserID: 1, Type: db.NodeEditTypeCreate},
 				{NodeID: 2, UserID: 2, Type: db.NodeEditTypeEdit},
 			},
-			ExpLenNodeEdits: 2,
+			ExpLenNodeEdits: 4, // history is kept, plus the deletion
+
 		},
 		{
 			Name:           "fail: edits present",
@@ -753,7 +1314,9 @@ func TestPostgresDB_DeleteNode(t *testing.T) {
 			ExpEdges: []Edge{
 				{Model: gorm.Model{ID: 2}, FromID: 3, ToID: 4},
 			},
-			ExpError: false,
+			ExpLenNodeEdits: 2,
+			ExpLenEdgeEdits: 3,
+			ExpError:        false,
 		},
 		{
 			Name:           "success: edits present, but admin-role overrides it",
@@ -768,7 +1331,7 @@ func TestPostgresDB_DeleteNode(t *testing.T) {
 				{NodeID: 1, UserID: 2 /*other user!*/, Type: db.NodeEditTypeEdit},
 				{NodeID: 2, UserID: 1, Type: db.NodeEditTypeCreate},
 			},
-			ExpLenNodeEdits: 1,
+			ExpLenNodeEdits: 4,
 		},
 	} {
 		t.Run(test.Name, func(t *testing.T) {
@@ -811,6 +1374,14 @@ func TestPostgresDB_DeleteNode(t *testing.T) {
 				edges := []Edge{}
 				assert.NoError(pg.db.Find(&edges).Error)
 				assert.Len(edges, len(test.ExpEdges))
+				edgeedits := []EdgeEdit{}
+				assert.NoError(pg.db.Find(&edgeedits).Error)
+				assert.Len(edgeedits, test.ExpLenEdgeEdits)
+			}
+			if !test.ExpError {
+				node := Node{}
+				assert.NoError(pg.db.Unscoped().First(&node, atoi(test.NodeIDToDelete)).Error, "node must be kept in the trash")
+				assert.True(node.DeletedAt.Valid)
 			}
 		})
 	}
@@ -846,7 +1417,7 @@ func TestPostgresDB_DeleteEdge(t *testing.T) {
 				{EdgeID: 2, UserID: 2, Type: db.EdgeEditTypeVote, Weight: 3.3},
 			},
 			ExpLenEdges:     1,
-			ExpLenEdgeEdits: 2,
+			ExpLenEdgeEdits: 4, // history is kept, plus the deletion
 		},
 		{
 			Name:           "fail: votes exist from other users",
@@ -886,7 +1457,7 @@ func TestPostgresDB_DeleteEdge(t *testing.T) {
 				{EdgeID: 2, UserID: 2, Type: db.EdgeEditTypeVote, Weight: 3.3},
 			},
 			ExpLenEdges:     1,
-			ExpLenEdgeEdits: 2,
+			ExpLenEdgeEdits: 5,
 		},
 	} {
 		t.Run(test.Name, func(t *testing.T) {
@@ -916,7 +1487,9 @@ func TestPostgresDB_DeleteEdge(t *testing.T) {
 			edgeedits := []EdgeEdit{}
 			assert.NoError(pg.db.Find(&edgeedits).Error)
 			edges := []Edge{}
-			assert.NoError(pg.db.Unscoped().Find(&edges).Error) // find soft-deleted edges
+			assert.NoError(pg.db.Find(&edges).Error)
+			deletedEdges := []Edge{}
+			assert.NoError(pg.db.Unscoped().Where("deleted_at IS NOT NULL").Find(&deletedEdges).Error)
 			if test.ExpError {
 				assert.Error(err)
 				assert.Len(edgeedits, len(test.PreexistingEdgeEdits))
@@ -924,11 +1497,169 @@ func TestPostgresDB_DeleteEdge(t *testing.T) {
 				assert.NoError(err)
 				assert.Len(edgeedits, test.ExpLenEdgeEdits)
 				assert.Len(edges, test.ExpLenEdges)
+				assert.Len(deletedEdges, 1, "edge must be kept in the trash")
+			}
+		})
+	}
+}
+
+func setupTrashTestData(t *testing.T, pg *PostgresDB) {
+	assert := assert.New(t)
+	users := []User{
+		{Model: gorm.Model{ID: 1}, Username: "current", PasswordHash: "0", EMail: "a@b"},
+		{Model: gorm.Model{ID: 2}, Username: "i'm admin", PasswordHash: "1", EMail: "ad@m",
+			Roles: []Role{{Role: db.RoleAdmin}}},
+	}
+	for _, user := range users {
+		assert.NoError(pg.db.Create(&user).Error)
+	}
+	for _, node := range []Node{
+		{Model: gorm.Model{ID: 1}, Description: db.Text{"en": "a"}},
+		{Model: gorm.Model{ID: 2}, Description: db.Text{"en": "b"}},
+		{Model: gorm.Model{ID: 3}, Description: db.Text{"en": "c"}},
+	} {
+		assert.NoError(pg.db.Create(&node).Error)
+	}
+	for _, edge := range []Edge{
+		{Model: gorm.Model{ID: 1}, FromID: 1, ToID: 2, Weight: 2},
+		{Model: gorm.Model{ID: 2}, FromID: 2, ToID: 3, Weight: 3},
+	} {
+		assert.NoError(pg.db.Create(&edge).Error)
+	}
+	for _, edgeedit := range []EdgeEdit{
+		{EdgeID: 1, UserID: 1, Type: db.EdgeEditTypeCreate, Weight: 2},
+		{EdgeID: 2, UserID: 1, Type: db.EdgeEditTypeCreate, Weight: 3},
+	} {
+		assert.NoError(pg.db.Create(&edgeedit).Error)
+	}
+}
+
+func TestPostgresDB_DeletedNodes(t *testing.T) {
+	pg := setupDB(t)
+	ctx := context.Background()
+	assert := assert.New(t)
+	setupTrashTestData(t, pg)
+	assert.NoError(pg.db.Create(&NodeEdit{NodeID: 3, UserID: 1, Type: db.NodeEditTypeCreate}).Error)
+	assert.NoError(pg.DeleteEdge(ctx, db.User{Document: db.Document{Key: "1"}}, "2"))
+	assert.NoError(pg.DeleteNode(ctx, db.User{Document: db.Document{Key: "1"}}, "3"))
+	_, err := pg.DeletedNodes(ctx, db.User{Document: db.Document{Key: "1"}})
+	assert.Error(err, "only admins may list the trash")
+	deleted, err := pg.DeletedNodes(ctx, db.User{Document: db.Document{Key: "2"}})
+	assert.NoError(err)
+	if assert.Len(deleted, 1) {
+		assert.Equal("3", deleted[0].Node.ID)
+		assert.Equal("c", deleted[0].Node.Description)
+		assert.Equal("current", *deleted[0].DeletedBy)
+	}
+	deletedEdges, err := pg.DeletedEdges(ctx, db.User{Document: db.Document{Key: "2"}})
+	assert.NoError(err)
+	if assert.Len(deletedEdges, 1) {
+		assert.Equal(&model.Edge{ID: "2", From: "2", To: "3", Weight: 3, Type: model.EdgeTypePrerequisite}, deletedEdges[0].Edge)
+	}
+}
+
+func TestPostgresDB_RestoreEdge(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		UserID   string
+		Setup    func(*testing.T, *PostgresDB)
+		ExpError bool
+	}{
+		{
+			Name:   "success",
+			UserID: "2",
+		},
+		{
+			Name:     "fail: not an admin",
+			UserID:   "1",
+			ExpError: true,
+		},
+		{
+			Name:   "fail: node of edge is in the trash",
+			UserID: "2",
+			Setup: func(t *testing.T, pg *PostgresDB) {
+				assert.NoError(t, pg.db.Delete(&Node{}, 1).Error)
+			},
+			ExpError: true,
+		},
+		{
+			Name:   "fail: edge was re-created in the meantime",
+			UserID: "2",
+			Setup: func(t *testing.T, pg *PostgresDB) {
+				assert.NoError(t, pg.db.Create(&Edge{Model: gorm.Model{ID: 3}, FromID: 1, ToID: 2}).Error)
+			},
+			ExpError: true,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			pg := setupDB(t)
+			ctx := context.Background()
+			assert := assert.New(t)
+			setupTrashTestData(t, pg)
+			assert.NoError(pg.DeleteEdge(ctx, db.User{Document: db.Document{Key: "1"}}, "1"))
+			if test.Setup != nil {
+				test.Setup(t, pg)
 			}
+			err := pg.RestoreEdge(ctx, db.User{Document: db.Document{Key: test.UserID}}, "1")
+			edge := Edge{}
+			if test.ExpError {
+				assert.Error(err)
+				assert.Error(pg.db.First(&edge, 1).Error)
+				return
+			}
+			assert.NoError(err)
+			assert.NoError(pg.db.First(&edge, 1).Error)
+			edgeedit := EdgeEdit{}
+			assert.NoError(pg.db.Order("id DESC").First(&edgeedit).Error)
+			assert.Equal(db.EdgeEditTypeRestore, edgeedit.Type)
 		})
 	}
 }
 
+func TestPostgresDB_RestoreNode(t *testing.T) {
+	pg := setupDB(t)
+	ctx := context.Background()
+	assert := assert.New(t)
+	setupTrashTestData(t, pg)
+	assert.NoError(pg.db.Create(&NodeEdit{NodeID: 3, UserID: 1, Type: db.NodeEditTypeCreate}).Error)
+	assert.NoError(pg.DeleteEdge(ctx, db.User{Document: db.Document{Key: "1"}}, "2"))
+	assert.NoError(pg.DeleteNode(ctx, db.User{Document: db.Document{Key: "1"}}, "3"))
+	assert.Error(pg.RestoreNode(ctx, db.User{Document: db.Document{Key: "1"}}, "3"), "only admins may restore")
+	assert.Error(pg.RestoreNode(ctx, db.User{Document: db.Document{Key: "2"}}, "1"), "node 1 is not deleted")
+	assert.NoError(pg.RestoreNode(ctx, db.User{Document: db.Document{Key: "2"}}, "3"))
+	node, err := pg.Node(ctx, "3")
+	assert.NoError(err)
+	assert.Equal("c", node.Description)
+	edits, err := pg.NodeEdits(ctx, "3")
+	assert.NoError(err)
+	types := []model.NodeEditType{}
+	for _, edit := range edits {
+		types = append(types, edit.Type)
+	}
+	assert.Equal([]model.NodeEditType{model.NodeEditTypeCreate, model.NodeEditTypeDelete, model.NodeEditTypeRestore}, types)
+}
+
+func TestPostgresDB_PurgeTrash(t *testing.T) {
+	pg := setupDB(t)
+	ctx := context.Background()
+	assert := assert.New(t)
+	setupTrashTestData(t, pg)
+	old, recent := time.Now().Add(-48*time.Hour), time.Now().Add(-time.Hour)
+	assert.NoError(pg.db.Model(&Edge{}).Where("id = ?", 2).Update("deleted_at", old).Error)
+	assert.NoError(pg.db.Model(&Node{}).Where("id = ?", 3).Update("deleted_at", old).Error)
+	assert.NoError(pg.db.Model(&Edge{}).Where("id = ?", 1).Update("deleted_at", recent).Error)
+	assert.NoError(pg.PurgeTrash(ctx, time.Now().Add(-24*time.Hour)))
+	nodes, edges, edgeedits := []Node{}, []Edge{}, []EdgeEdit{}
+	assert.NoError(pg.db.Unscoped().Find(&nodes).Error)
+	assert.NoError(pg.db.Unscoped().Find(&edges).Error)
+	assert.NoError(pg.db.Find(&edgeedits).Error)
+	assert.Len(nodes, 2)
+	if assert.Len(edges, 1) {
+		assert.Equal(uint(1), edges[0].ID)
+	}
+	assert.Len(edgeedits, 1)
+}
+
 func TestPostgresDB_Logout(t *testing.T) {
 	for _, test := range []struct {
 		Name                            string
@@ -1195,7 +1926,7 @@ func TestPostgresDB_NodeEdits(t *testing.T) {
 				{NodeID: 2, UserID: 1, Type: db.NodeEditTypeCreate, NewDescription: db.Text{"en": "bb"}, NewResources: db.Text{"en": "QQ"}},
 			},
 			ExpEdits: []*model.NodeEdit{
-				{Username: "user1", Type: model.NodeEditTypeCreate, NewDescription: "aa", NewResources: strptr("RR")},
+				{Username: "user1", Type: model.NodeEditTypeCreate, NewDescription: "aa", NewResources: strptr("RR"), Translations: translationsOf(db.Text{"en": "aa"}, db.Text{"en": "RR"})},
 			},
 		},
 		{
@@ -1211,9 +1942,9 @@ func TestPostgresDB_NodeEdits(t *testing.T) {
 				{NodeID: 1, UserID: 2, NewDescription: db.Text{"en": "aaaa"}, Type: db.NodeEditTypeEdit},
 			},
 			ExpEdits: []*model.NodeEdit{
-				{Username: "user1", NewDescription: "aa", Type: model.NodeEditTypeCreate},
-				{Username: "user1", NewDescription: "aaa", Type: model.NodeEditTypeEdit},
-				{Username: "user2", NewDescription: "aaaa", Type: model.NodeEditTypeEdit},
+				{Username: "user1", NewDescription: "aa", Type: model.NodeEditTypeCreate, Translations: translationsOf(db.Text{"en": "aa"}, nil)},
+				{Username: "user1", NewDescription: "aaa", Type: model.NodeEditTypeEdit, Translations: translationsOf(db.Text{"en": "aaa"}, nil)},
+				{Username: "user2", NewDescription: "aaaa", Type: model.NodeEditTypeEdit, Translations: translationsOf(db.Text{"en": "aaaa"}, nil)},
 			},
 		},
 		{
@@ -1252,6 +1983,7 @@ func TestPostgresDB_NodeEdits(t *testing.T) {
 				assert.Equal(test.ExpEdits[i].Type, edits[i].Type)
 				assert.Equal(test.ExpEdits[i].NewResources, edits[i].NewResources)
 				assert.Equal(test.ExpEdits[i].NewDescription, edits[i].NewDescription)
+				assert.Equal(test.ExpEdits[i].Translations, edits[i].Translations)
 				assert.True(edits[i].UpdatedAt.After(time.Now().Add(-60 * time.Minute))) // just check that it's not time.Time(0)
 			}
 			if test.ExpError {
@@ -1461,7 +2193,7 @@ func TestPostgresDB_NodeMatchFuzzy(t *testing.T) {
 			for _, node := range test.NodesToCreate {
 				assert.NoError(pg.db.Create(&node).Error)
 			}
-			nodes, err := pg.NodeMatchFuzzy(ctx, test.Substring)
+			nodes, err := pg.NodeMatchFuzzy(ctx, test.Substring, nil)
 			if test.ExpError {
 				assert.Error(err)
 				return
@@ -1506,3 +2238,700 @@ func TestPostgresDB_NodeMatchFuzzy(t *testing.T) {
 // 	})
 // }
 // }
+
+func TestPostgresDB_Tags(t *testing.T) {
+	pg := setupDB(t)
+	ctx := middleware.TestingCtxNewWithLanguage(context.Background(), "en")
+	assert := assert.New(t)
+	user := User{Username: "123", PasswordHash: "000", EMail: "a@b"}
+	assert.NoError(pg.db.Create(&user).Error)
+	dbUser := db.User{Document: db.Document{Key: itoa(user.ID)}}
+	for _, node := range []Node{
+		{Description: db.Text{"en": "Apple"}},
+		{Description: db.Text{"en": "Apple tree"}},
+		{Description: db.Text{"en": "Cell"}},
+	} {
+		assert.NoError(pg.db.Create(&node).Error)
+	}
+	assert.NoError(pg.db.Create(&Edge{FromID: 1, ToID: 2}).Error)
+	assert.NoError(pg.db.Create(&Edge{FromID: 2, ToID: 3}).Error)
+	biology, err := pg.CreateTag(ctx, dbUser, &model.Text{Translations: []*model.Translation{
+		{Language: "en", Content: "biology"},
+		{Language: "de", Content: "Biologie"},
+	}})
+	assert.NoError(err)
+	_, err = pg.CreateTag(ctx, dbUser, &model.Text{})
+	assert.Error(err, "empty tag name")
+	tags, err := pg.Tags(ctx)
+	assert.NoError(err)
+	assert.Equal([]*model.Tag{{ID: biology, Name: "biology"}}, tags)
+
+	assert.NoError(pg.TagNode(ctx, dbUser, "2", biology))
+	assert.NoError(pg.TagNode(ctx, dbUser, "3", biology))
+	assert.Error(pg.TagNode(ctx, dbUser, "4", biology), "node does not exist")
+	graph, err := pg.Graph(ctx, db.GraphFilter{TagIDs: []string{biology}})
+	assert.NoError(err)
+	assert.Equal(&model.Graph{
+		Nodes: []*model.Node{
+			{ID: "2", Description: "Apple tree", Tags: tags, Translations: translationsOf(db.Text{"en": "Apple tree"}, nil)},
+			{ID: "3", Description: "Cell", Tags: tags, Translations: translationsOf(db.Text{"en": "Cell"}, nil)},
+		},
+		Edges: []*model.Edge{{ID: "2", From: "2", To: "3", Type: model.EdgeTypePrerequisite}},
+	}, graph)
+	nodes, err := pg.NodeMatchFuzzy(ctx, "apple", []string{biology})
+	assert.NoError(err)
+	if assert.Len(nodes, 1) {
+		assert.Equal("2", nodes[0].ID)
+	}
+
+	assert.NoError(pg.UntagNode(ctx, dbUser, "3", biology))
+	node, err := pg.Node(ctx, "3")
+	assert.NoError(err)
+	assert.Empty(node.Tags)
+	edits, err := pg.NodeEdits(ctx, "3")
+	assert.NoError(err)
+	if assert.Len(edits, 2) {
+		assert.Equal(model.NodeEditTypeTag, edits[0].Type)
+		assert.Equal(model.NodeEditTypeUntag, edits[1].Type)
+		assert.Equal(tags[0], edits[1].Tag)
+	}
+}
+
+func TestPostgresDB_Resources(t *testing.T) {
+	pg := setupDB(t)
+	ctx := middleware.TestingCtxNewWithLanguage(context.Background(), "en")
+	assert := assert.New(t)
+	users := []User{
+		{Username: "author", PasswordHash: "0", EMail: "a@b"},
+		{Username: "voter", PasswordHash: "1", EMail: "c@d"},
+	}
+	for i := range users {
+		assert.NoError(pg.db.Create(&users[i]).Error)
+	}
+	author := db.User{Document: db.Document{Key: itoa(users[0].ID)}}
+	voter := db.User{Document: db.Document{Key: itoa(users[1].ID)}}
+	assert.NoError(pg.db.Create(&Node{Description: db.Text{"en": "Calculus"}}).Error)
+	minutes := 12
+	video := model.ResourceInput{
+		URL:             "https://example.com/video",
+		Title:           &model.Text{Translations: []*model.Translation{{Language: "en", Content: "Limits explained"}}},
+		Kind:            model.ResourceKindVideo,
+		Language:        "en",
+		DurationMinutes: &minutes,
+	}
+	book := model.ResourceInput{
+		URL:      "https://example.com/book",
+		Title:    &model.Text{Translations: []*model.Translation{{Language: "en", Content: "Calculus"}}},
+		Kind:     model.ResourceKindBook,
+		Language: "de",
+	}
+	_, err := pg.CreateResource(ctx, author, "1", model.ResourceInput{URL: "not a url", Kind: model.ResourceKindBook, Language: "en"})
+	assert.Error(err, "invalid URL")
+	_, err = pg.CreateResource(ctx, author, "2", book)
+	assert.Error(err, "node does not exist")
+	videoID, err := pg.CreateResource(ctx, author, "1", video)
+	assert.NoError(err)
+	bookID, err := pg.CreateResource(ctx, author, "1", book)
+	assert.NoError(err)
+
+	assert.NoError(pg.VoteResource(ctx, voter, bookID))
+	assert.NoError(pg.VoteResource(ctx, voter, bookID), "voting twice counts once")
+	resources, err := pg.NodeResources(ctx, []string{"1", "2"})
+	assert.NoError(err)
+	assert.Equal(map[string][]*model.Resource{"1": {
+		{
+			ID: bookID, URL: "https://example.com/book", Title: "Calculus", Kind: model.ResourceKindBook, Language: "de", Votes: 1,
+			Translations: []*model.TranslationOut{{Language: "en", Field: model.TranslationFieldTitle, Content: "Calculus"}},
+		},
+		{
+			ID: videoID, URL: "https://example.com/video", Title: "Limits explained", Kind: model.ResourceKindVideo, Language: "en", DurationMinutes: &minutes,
+			Translations: []*model.TranslationOut{{Language: "en", Field: model.TranslationFieldTitle, Content: "Limits explained"}},
+		},
+	}}, resources, "nodes without resources are omitted")
+
+	video.URL = "https://example.com/better-video"
+	assert.NoError(pg.EditResource(ctx, voter, videoID, video))
+	assert.Error(pg.DeleteResource(ctx, author, videoID), "edited by another user")
+	assert.NoError(pg.DeleteResource(ctx, author, bookID))
+	resources, err = pg.NodeResources(ctx, []string{"1"})
+	assert.NoError(err)
+	if assert.Len(resources["1"], 1) {
+		assert.Equal("https://example.com/better-video", resources["1"][0].URL)
+	}
+	edits, err := pg.ResourceEdits(ctx, bookID)
+	assert.NoError(err)
+	types := []model.ResourceEditType{}
+	for _, edit := range edits {
+		types = append(types, edit.Type)
+	}
+	assert.Equal([]model.ResourceEditType{model.ResourceEditTypeCreate, model.ResourceEditTypeDelete}, types)
+}
+
+func TestPostgresDB_MergeNodes(t *testing.T) {
+	setup := func(t *testing.T) (*PostgresDB, db.User, db.User) {
+		pg := setupDB(t)
+		assert := assert.New(t)
+		users := []User{
+			{Username: "someone", PasswordHash: "0", EMail: "a@b"},
+			{Username: "moderator", PasswordHash: "1", EMail: "c@d", Roles: []Role{{Role: db.RoleModerator}}},
+		}
+		for i := range users {
+			assert.NoError(pg.db.Create(&users[i]).Error)
+		}
+		for _, node := range []Node{
+			{Description: db.Text{"en": "Apple"}},
+			{Description: db.Text{"en": "apple (fruit)", "de": "Apfel"}},
+			{Description: db.Text{"en": "Tree"}},
+			{Description: db.Text{"en": "Seed"}},
+		} {
+			assert.NoError(pg.db.Create(&node).Error)
+		}
+		return pg, db.User{Document: db.Document{Key: itoa(users[0].ID)}}, db.User{Document: db.Document{Key: itoa(users[1].ID)}}
+	}
+	t.Run("merge edges, translations and history", func(t *testing.T) {
+		pg, someone, moderator := setup(t)
+		ctx := middleware.TestingCtxNewWithLanguage(context.Background(), "de")
+		assert := assert.New(t)
+		// IDs are assigned in order of creation: 1, 2, 3
+		for _, edge := range []Edge{
+			{FromID: 1, ToID: 3, Weight: 2},
+			{FromID: 2, ToID: 3, Weight: 4},
+			{FromID: 2, ToID: 1, Weight: 5},
+			{FromID: 4, ToID: 2, Weight: 6},
+		} {
+			assert.NoError(pg.db.Create(&edge).Error)
+		}
+		for _, edgeedit := range []EdgeEdit{
+			{EdgeID: 1, UserID: 1, Type: db.EdgeEditTypeCreate, Weight: 2},
+			{EdgeID: 2, UserID: 2, Type: db.EdgeEditTypeCreate, Weight: 4},
+		} {
+			assert.NoError(pg.db.Create(&edgeedit).Error)
+		}
+		assert.NoError(pg.db.Create(&NodeEdit{NodeID: 2, UserID: 1, Type: db.NodeEditTypeCreate, NewDescription: db.Text{"en": "apple (fruit)"}}).Error)
+
+		assert.Error(pg.MergeNodes(ctx, someone, "1", "2"), "only moderators and admins may merge")
+		assert.Error(pg.MergeNodes(ctx, moderator, "1", "1"))
+		assert.NoError(pg.MergeNodes(ctx, moderator, "1", "2"))
+
+		graph, err := pg.Graph(ctx, db.GraphFilter{})
+		assert.NoError(err)
+		assert.Equal(&model.Graph{
+			Nodes: []*model.Node{
+				{ID: "1", Description: "Apfel", Translations: translationsOf(db.Text{"en": "Apple", "de": "Apfel"}, nil)},
+				{ID: "3", Description: "🇺🇸 Tree", Translations: translationsOf(db.Text{"en": "Tree"}, nil)},
+				{ID: "4", Description: "🇺🇸 Seed", Translations: translationsOf(db.Text{"en": "Seed"}, nil)},
+			},
+			Edges: []*model.Edge{
+				{ID: "1", From: "1", To: "3", Weight: 3, Type: model.EdgeTypePrerequisite},
+				{ID: "4", From: "4", To: "1", Weight: 6, Type: model.EdgeTypePrerequisite},
+			},
+		}, graph)
+		ids, err := pg.ResolveNodeIDs(ctx, []string{"2"})
+		assert.NoError(err)
+		assert.Equal([]string{"1"}, ids, "merged node must resolve to the kept node")
+		edits, err := pg.NodeEdits(ctx, "1")
+		assert.NoError(err)
+		if assert.Len(edits, 2) {
+			assert.Equal(model.NodeEditTypeCreate, edits[0].Type)
+			assert.Equal(model.NodeEditTypeMerge, edits[1].Type)
+		}
+	})
+	t.Run("merged IDs resolve for edits, new edges and votes", func(t *testing.T) {
+		pg, someone, moderator := setup(t)
+		ctx := context.Background()
+		assert := assert.New(t)
+		assert.NoError(pg.db.Create(&Node{Description: db.Text{"en": "Root"}}).Error)
+		assert.NoError(pg.db.Create(&Edge{FromID: 1, ToID: 3, Weight: 2}).Error)
+		assert.NoError(pg.db.Create(&Edge{FromID: 2, ToID: 3, Weight: 4}).Error)
+		assert.NoError(pg.MergeNodes(ctx, moderator, "1", "2"))
+		assert.NoError(pg.MergeNodes(ctx, moderator, "4", "1"))
+
+		ids, err := pg.ResolveNodeIDs(ctx, []string{"2", "1", "3", "4"})
+		assert.NoError(err)
+		assert.Equal([]string{"4", "4", "3", "4"}, ids, "redirects are followed across merges")
+		edgeIDs, err := pg.ResolveEdgeIDs(ctx, []string{"2", "1"})
+		assert.NoError(err)
+		assert.Equal([]string{"1", "1"}, edgeIDs, "edge 2 was folded into edge 1")
+
+		description := &model.Text{Translations: []*model.Translation{{Language: "en", Content: "Seed (botany)"}}}
+		assert.NoError(pg.EditNode(ctx, someone, ids[0], description, nil))
+		node := Node{}
+		assert.NoError(pg.db.First(&node, 4).Error)
+		assert.Equal("Seed (botany)", node.Description["en"])
+		_, err = pg.CreateEdge(ctx, someone, ids[0], "5", 3, db.EdgeTypePrerequisite)
+		assert.NoError(err)
+		assert.NoError(pg.AddEdgeWeightVote(ctx, someone, edgeIDs[0], 7))
+	})
+	t.Run("deleted edges duplicating an edge after the merge cannot be restored", func(t *testing.T) {
+		pg, _, moderator := setup(t)
+		ctx := context.Background()
+		assert := assert.New(t)
+		admin := User{Username: "admin", PasswordHash: "2", EMail: "e@f", Roles: []Role{{Role: db.RoleAdmin}}}
+		assert.NoError(pg.db.Create(&admin).Error)
+		assert.NoError(pg.db.Create(&Edge{FromID: 1, ToID: 3, Weight: 2}).Error)
+		deleted := Edge{FromID: 2, ToID: 3, Weight: 4, Model: gorm.Model{DeletedAt: gorm.DeletedAt{Time: TEST_TimeNow, Valid: true}}}
+		assert.NoError(pg.db.Create(&deleted).Error)
+		assert.NoError(pg.MergeNodes(ctx, moderator, "1", "2"))
+
+		err := pg.RestoreEdge(ctx, db.User{Document: db.Document{Key: itoa(admin.ID)}}, itoa(deleted.ID))
+		if assert.Error(err) {
+			assert.Contains(err.Error(), "duplicates the edge with id='1'")
+		}
+		edge := Edge{}
+		assert.NoError(pg.db.Unscoped().First(&edge, deleted.ID).Error)
+		assert.True(edge.DeletedAt.Valid, "stays in the trash")
+		assert.Equal(uint(1), edge.FromID)
+	})
+	t.Run("merge must not create prerequisite cycles", func(t *testing.T) {
+		pg, _, moderator := setup(t)
+		ctx := context.Background()
+		assert := assert.New(t)
+		assert.NoError(pg.db.Create(&Edge{FromID: 3, ToID: 1}).Error)
+		assert.NoError(pg.db.Create(&Edge{FromID: 2, ToID: 3}).Error)
+		err := pg.MergeNodes(ctx, moderator, "1", "2")
+		cycleErr := &db.CycleError{}
+		assert.ErrorAs(err, &cycleErr)
+		var nodes int64
+		assert.NoError(pg.db.Model(&Node{}).Count(&nodes).Error)
+		assert.Equal(int64(4), nodes)
+	})
+}
+
+func TestPostgresDB_CreateNode_Duplicates(t *testing.T) {
+	pg := setupDB(t)
+	ctx := context.Background()
+	assert := assert.New(t)
+	user := User{Username: "123", PasswordHash: "000", EMail: "a@b"}
+	assert.NoError(pg.db.Create(&user).Error)
+	assert.NoError(pg.db.Create(&Node{Description: db.Text{"en": "Apple tree", "de": "Apfelbaum"}}).Error)
+	dbUser := db.User{Document: db.Document{Key: itoa(user.ID)}}
+	text := func(lang, content string) *model.Text {
+		return &model.Text{Translations: []*model.Translation{{Language: lang, Content: content}}}
+	}
+
+	for _, description := range []*model.Text{text("en", "apple trees"), text("de", "Der Apfelbaum")} {
+		_, err := pg.CreateNode(ctx, dbUser, description, nil, false)
+		duplicateErr := &db.DuplicateNodesError{}
+		if assert.ErrorAs(err, &duplicateErr) {
+			assert.Equal([]*model.Node{{ID: "1", Description: "Apple tree", Translations: translationsOf(db.Text{"en": "Apple tree", "de": "Apfelbaum"}, nil)}}, duplicateErr.Duplicates)
+		}
+	}
+	id, err := pg.CreateNode(ctx, dbUser, text("en", "Cell"), nil, false)
+	assert.NoError(err, "dissimilar nodes are created")
+	assert.Equal("2", id)
+	id, err = pg.CreateNode(ctx, dbUser, text("en", "apple trees"), nil, true)
+	assert.NoError(err, "similar nodes are created by force")
+	assert.Equal("3", id)
+}
+
+func TestPostgresDB_NodeMatchFuzzy_Languages(t *testing.T) {
+	pg := setupDB(t)
+	assert := assert.New(t)
+	user := User{Username: "123", PasswordHash: "000", EMail: "a@b"}
+	assert.NoError(pg.db.Create(&user).Error)
+	dbUser := db.User{Document: db.Document{Key: itoa(user.ID)}}
+	for _, description := range []*model.Text{
+		{Translations: []*model.Translation{{Language: "en", Content: "Apple"}, {Language: "de", Content: "Apfel"}}},
+		{Translations: []*model.Translation{{Language: "de", Content: "Apfelbaum"}}},
+		{Translations: []*model.Translation{{Language: "en", Content: "Banana"}}},
+	} {
+		_, err := pg.CreateNode(context.Background(), dbUser, description, nil, true)
+		assert.NoError(err)
+	}
+	indexExists := func(name string) func() bool {
+		return func() bool {
+			var idxCount int
+			assert.NoError(pg.db.Raw(`SELECT COUNT(*) FROM pg_indexes WHERE indexname = ?`, name).Scan(&idxCount).Error)
+			return idxCount == 1
+		}
+	}
+	for _, lang := range supportedLanguages() {
+		assert.True(indexExists(trigramIndexName(lang))(), "supported languages are indexed by init: %s", lang)
+	}
+	_, err := pg.CreateNode(context.Background(), dbUser, &model.Text{Translations: []*model.Translation{{Language: "pt", Content: "Maçã"}}}, nil, true)
+	assert.Error(err, "unsupported languages are rejected")
+	assert.False(indexExists("idx_nodes_description_pt_trgm")())
+
+	matches := func(nodes []*model.Node) []string {
+		res := []string{}
+		for _, node := range nodes {
+			res = append(res, node.ID+":"+*node.MatchedLanguage)
+		}
+		return res
+	}
+	nodes, err := pg.NodeMatchFuzzy(middleware.TestingCtxNewWithLanguage(context.Background(), "de"), "apfelbaum", nil)
+	assert.NoError(err)
+	assert.Equal([]string{"2:de", "1:de"}, matches(nodes))
+	nodes, err = pg.NodeMatchFuzzy(middleware.TestingCtxNewWithLanguage(context.Background(), "en"), "apfelbaum", nil)
+	assert.NoError(err)
+	assert.Equal([]string{"2:de", "1:de"}, matches(nodes), "other languages are searched as fallback")
+	nodes, err = pg.NodeMatchFuzzy(middleware.TestingCtxNewWithLanguage(context.Background(), "en"), "apple", nil)
+	assert.NoError(err)
+	assert.Equal([]string{"1:en"}, matches(nodes))
+
+}
+
+func TestPostgresDB_Search(t *testing.T) {
+	pg := setupDB(t)
+	ctx := context.Background()
+	assert := assert.New(t)
+	for _, node := range []Node{
+		{Description: db.Text{"en": "Photosynthesis", "de": "Photosynthese"}, Resources: db.Text{"en": "How plants convert sunlight into sugar"}},
+		{Description: db.Text{"en": "Plants", "de": "Pflanzen"}},
+		{Description: db.Text{"en": "Sugars and starches"}},
+		{Description: db.Text{"en": "Deleted plants"}, Model: gorm.Model{DeletedAt: gorm.DeletedAt{Time: TEST_TimeNow, Valid: true}}},
+	} {
+		assert.NoError(pg.db.Create(&node).Error)
+	}
+
+	hits, err := pg.Search(ctx, "plant", "en", 10, 0)
+	assert.NoError(err)
+	if assert.Len(hits, 2, "stemmed words are found in description and resources, deleted nodes are not") {
+		assert.Equal("2", hits[0].Node.ID, "matches in the description rank higher")
+		assert.Equal("<b>Plants</b>", hits[0].DescriptionSnippet)
+		assert.Nil(hits[0].ResourcesSnippet)
+		assert.Equal("1", hits[1].Node.ID)
+		if assert.NotNil(hits[1].ResourcesSnippet) {
+			assert.Contains(*hits[1].ResourcesSnippet, "<b>plants</b>")
+		}
+	}
+	hits, err = pg.Search(ctx, "plant", "en", 1, 1)
+	assert.NoError(err)
+	if assert.Len(hits, 1) {
+		assert.Equal("1", hits[0].Node.ID)
+	}
+	hits, err = pg.Search(ctx, "pflanze", "de", 10, 0)
+	assert.NoError(err)
+	if assert.Len(hits, 1) {
+		assert.Equal("2", hits[0].Node.ID)
+		assert.Equal("Pflanzen", hits[0].Node.Description)
+	}
+	_, err = pg.Search(ctx, "plant", "xx", 10, 0)
+	assert.Error(err)
+}
+
+func TestPostgresDB_TranslationCoverage(t *testing.T) {
+	pg := setupDB(t)
+	ctx := middleware.TestingCtxNewWithLanguage(context.Background(), "en")
+	assert := assert.New(t)
+	for _, node := range []Node{
+		{Description: db.Text{"en": "A", "de": "A"}, Resources: db.Text{"en": "a"}},
+		{Description: db.Text{"en": "B"}},
+		{Description: db.Text{"en": "C"}},
+		{Description: db.Text{"de": "D"}, Resources: db.Text{"de": "d"}},
+		{Description: db.Text{"en": "E"}, Model: gorm.Model{DeletedAt: gorm.DeletedAt{Time: TEST_TimeNow, Valid: true}}},
+	} {
+		assert.NoError(pg.db.Create(&node).Error)
+	}
+	coverage, err := pg.TranslationCoverage(ctx)
+	assert.NoError(err)
+	byLanguage := map[string]model.TranslationCoverage{}
+	for _, c := range coverage {
+		byLanguage[c.Language] = *c
+	}
+	assert.Len(coverage, len(LanguageToLanguageFlag))
+	assert.Equal(model.TranslationCoverage{Language: "en", Descriptions: 3, Resources: 1, Total: 4}, byLanguage["en"])
+	assert.Equal(model.TranslationCoverage{Language: "de", Descriptions: 2, Resources: 1, Total: 4}, byLanguage["de"])
+	assert.Equal(model.TranslationCoverage{Language: "fr", Descriptions: 0, Resources: 0, Total: 4}, byLanguage["fr"])
+
+	nodes, err := pg.UntranslatedNodes(ctx, "de", 1, "")
+	assert.NoError(err)
+	assert.Equal([]*model.Node{{ID: "2", Description: "B", Translations: translationsOf(db.Text{"en": "B"}, nil)}}, nodes)
+	nodes, err = pg.UntranslatedNodes(ctx, "de", 10, "2")
+	assert.NoError(err)
+	assert.Equal([]*model.Node{{ID: "3", Description: "C", Translations: translationsOf(db.Text{"en": "C"}, nil)}}, nodes)
+	nodes, err = pg.UntranslatedNodes(ctx, "en", 10, "")
+	assert.NoError(err)
+	assert.Equal([]*model.Node{{ID: "4", Description: "🇩🇪 D", Translations: translationsOf(db.Text{"de": "D"}, db.Text{"de": "d"})}}, nodes)
+	_, err = pg.UntranslatedNodes(ctx, "xx", 10, "")
+	assert.Error(err)
+}
+
+func TestPostgresDB_Translations(t *testing.T) {
+	pg := setupDB(t)
+	ctx := middleware.TestingCtxNewWithLanguage(context.Background(), "en")
+	assert := assert.New(t)
+	user := User{Username: "123", PasswordHash: "000", EMail: "a@b"}
+	assert.NoError(pg.db.Create(&user).Error)
+	node := Node{Description: db.Text{"en": "Apple", "de": "Apfel"}, Resources: db.Text{"de": "Obst"}}
+	assert.NoError(pg.db.Create(&node).Error)
+	edit := NodeEdit{NodeID: node.ID, UserID: user.ID, Type: db.NodeEditTypeCreate, NewDescription: db.Text{"en": "Apple"}}
+	assert.NoError(pg.db.Create(&edit).Error)
+	resource := Resource{NodeID: node.ID, URL: "https://a.b", Title: db.Text{"fr": "Pomme"}, Kind: db.ResourceKindArticle, Language: "fr"}
+	assert.NoError(pg.db.Create(&resource).Error)
+
+	modelNode, err := pg.Node(ctx, itoa(node.ID))
+	assert.NoError(err)
+	assert.Equal([]*model.TranslationOut{
+		{Language: "de", Field: model.TranslationFieldDescription, Content: "Apfel"},
+		{Language: "en", Field: model.TranslationFieldDescription, Content: "Apple"},
+		{Language: "de", Field: model.TranslationFieldResources, Content: "Obst"},
+	}, modelNode.Translations)
+	edits, err := pg.NodeEdits(ctx, itoa(node.ID))
+	assert.NoError(err)
+	if assert.Len(edits, 1) {
+		assert.Equal([]*model.TranslationOut{
+			{Language: "en", Field: model.TranslationFieldDescription, Content: "Apple"},
+		}, edits[0].Translations)
+	}
+	resources, err := pg.NodeResources(ctx, []string{itoa(node.ID)})
+	assert.NoError(err)
+	if assert.Len(resources[itoa(node.ID)], 1) {
+		assert.Equal([]*model.TranslationOut{
+			{Language: "fr", Field: model.TranslationFieldTitle, Content: "Pomme"},
+		}, resources[itoa(node.ID)][0].Translations)
+	}
+}
+
+func TestPostgresDB_ChangeTranslations(t *testing.T) {
+	pg := setupDB(t)
+	ctx := middleware.TestingCtxNewWithLanguage(context.Background(), "en")
+	assert := assert.New(t)
+	users := []User{
+		{Username: "someone", PasswordHash: "0", EMail: "a@b"},
+		{Username: "admin", PasswordHash: "1", EMail: "c@d", Roles: []Role{{Role: db.RoleAdmin}}},
+	}
+	for i := range users {
+		assert.NoError(pg.db.Create(&users[i]).Error)
+	}
+	someone := db.User{Document: db.Document{Key: itoa(users[0].ID)}}
+	admin := db.User{Document: db.Document{Key: itoa(users[1].ID)}}
+	for _, node := range []Node{
+		{Description: db.Text{"zh": "Apple", "de": "Apfel"}, Resources: db.Text{"zh": "fruit"}},
+		{Description: db.Text{"zh": "Pear"}},
+		{Description: db.Text{"zh": "香蕉"}},
+		{Description: db.Text{"zh": "Cherry", "en": "Cherry"}},
+	} {
+		assert.NoError(pg.db.Create(&node).Error)
+	}
+	for _, edit := range []NodeEdit{
+		{NodeID: 1, UserID: users[0].ID, Type: db.NodeEditTypeCreate},
+		{NodeID: 2, UserID: users[1].ID, Type: db.NodeEditTypeCreate},
+		{NodeID: 3, UserID: users[0].ID, Type: db.NodeEditTypeCreate},
+		{NodeID: 4, UserID: users[0].ID, Type: db.NodeEditTypeCreate},
+	} {
+		assert.NoError(pg.db.Create(&edit).Error)
+	}
+
+	filter := db.TranslationFilter{EditedBy: someone.Key, Matches: "^[a-zA-Z0-9]"}
+	_, err := pg.MoveTranslations(ctx, someone, filter, "zh", "en")
+	assert.Error(err, "only admins may change translations in bulk")
+	changed, err := pg.MoveTranslations(ctx, admin, filter, "zh", "en")
+	assert.NoError(err)
+	assert.Equal([]string{"1"}, changed, "nodes of other users, not matching or with an english translation are skipped")
+	node := Node{}
+	assert.NoError(pg.db.First(&node, 1).Error)
+	assert.Equal(db.Text{"en": "Apple", "de": "Apfel"}, node.Description)
+	assert.Equal(db.Text{"en": "fruit"}, node.Resources)
+
+	assert.NoError(pg.MoveTranslation(ctx, someone, "2", "zh", "en"))
+	assert.Error(pg.MoveTranslation(ctx, someone, "4", "zh", "en"), "english translation exists")
+	assert.NoError(pg.RemoveTranslation(ctx, someone, "4", "zh", model.TranslationFieldDescription))
+	assert.Error(pg.RemoveTranslation(ctx, someone, "4", "en", model.TranslationFieldDescription), "last description")
+
+	edits := []NodeEdit{}
+	assert.NoError(pg.db.Where("type IN ?", []db.NodeEditType{db.NodeEditTypeMoveTranslation, db.NodeEditTypeRemoveTranslation}).Order("id").Find(&edits).Error)
+	if assert.Len(edits, 3) {
+		assert.Equal(uint(1), edits[0].NodeID)
+		assert.Equal(db.NodeEditTypeMoveTranslation, edits[0].Type)
+		assert.Equal(db.Text{"en": "Apple", "de": "Apfel"}, edits[0].NewDescription)
+		assert.Equal(uint(2), edits[1].NodeID)
+		assert.Equal(db.NodeEditTypeMoveTranslation, edits[1].Type)
+		assert.Equal(uint(4), edits[2].NodeID)
+		assert.Equal(db.NodeEditTypeRemoveTranslation, edits[2].Type)
+		assert.Equal(db.Text{"en": "Cherry"}, edits[2].NewDescription)
+	}
+}
+
+func TestPostgresDB_RemoveTranslations(t *testing.T) {
+	pg := setupDB(t)
+	ctx := middleware.TestingCtxNewWithLanguage(context.Background(), "en")
+	assert := assert.New(t)
+	users := []User{
+		{Username: "someone", PasswordHash: "0", EMail: "a@b"},
+		{Username: "admin", PasswordHash: "1", EMail: "c@d", Roles: []Role{{Role: db.RoleAdmin}}},
+	}
+	for i := range users {
+		assert.NoError(pg.db.Create(&users[i]).Error)
+	}
+	someone := db.User{Document: db.Document{Key: itoa(users[0].ID)}}
+	admin := db.User{Document: db.Document{Key: itoa(users[1].ID)}}
+	for _, node := range []Node{
+		{Description: db.Text{"zh": "Apple", "en": "Apple"}, Resources: db.Text{"zh": "fruit"}},
+		{Description: db.Text{"zh": "Pear"}},
+		{Description: db.Text{"zh": "香蕉", "en": "Banana"}},
+	} {
+		assert.NoError(pg.db.Create(&node).Error)
+	}
+	for _, edit := range []NodeEdit{
+		{NodeID: 1, UserID: users[0].ID, Type: db.NodeEditTypeCreate},
+		{NodeID: 2, UserID: users[0].ID, Type: db.NodeEditTypeCreate},
+		{NodeID: 3, UserID: users[0].ID, Type: db.NodeEditTypeCreate},
+	} {
+		assert.NoError(pg.db.Create(&edit).Error)
+	}
+
+	filter := db.TranslationFilter{EditedBy: someone.Key, Matches: "^[a-zA-Z]"}
+	_, err := pg.RemoveTranslations(ctx, someone, filter, "zh", model.TranslationFieldDescription)
+	assert.Error(err, "only admins may change translations in bulk")
+	_, err = pg.RemoveTranslations(ctx, admin, filter, "zh", model.TranslationFieldTitle)
+	assert.Error(err, "nodes have no title")
+	changed, err := pg.RemoveTranslations(ctx, admin, filter, "zh", model.TranslationFieldDescription)
+	assert.NoError(err)
+	assert.Equal([]string{"1"}, changed, "nodes with only this description or not matching are skipped")
+	changed, err = pg.RemoveTranslations(ctx, admin, db.TranslationFilter{}, "zh", model.TranslationFieldResources)
+	assert.NoError(err)
+	assert.Equal([]string{"1"}, changed)
+	nodes := []Node{}
+	assert.NoError(pg.db.Order("id").Find(&nodes).Error)
+	if assert.Len(nodes, 3) {
+		assert.Equal(db.Text{"en": "Apple"}, nodes[0].Description)
+		assert.Empty(nodes[0].Resources)
+		assert.Equal(db.Text{"zh": "Pear"}, nodes[1].Description)
+		assert.Equal(db.Text{"zh": "香蕉", "en": "Banana"}, nodes[2].Description)
+	}
+	edits := []NodeEdit{}
+	assert.NoError(pg.db.Where("type = ?", db.NodeEditTypeRemoveTranslation).Find(&edits).Error)
+	assert.Len(edits, 2)
+}
+
+func TestPostgresDB_RetractEdgeWeightVote(t *testing.T) {
+	pg := setupDB(t)
+	ctx := context.Background()
+	assert := assert.New(t)
+	for _, user := range []User{
+		{Model: gorm.Model{ID: 111}, Username: "asdf", PasswordHash: "000", EMail: "a@b"},
+		{Model: gorm.Model{ID: 222}, Username: "fasd", PasswordHash: "111", EMail: "c@d"},
+	} {
+		assert.NoError(pg.db.Create(&user).Error)
+	}
+	for _, node := range []Node{
+		{Model: gorm.Model{ID: 1}, Description: db.Text{"en": "A"}},
+		{Model: gorm.Model{ID: 2}, Description: db.Text{"en": "B"}},
+	} {
+		assert.NoError(pg.db.Create(&node).Error)
+	}
+	assert.NoError(pg.db.Create(&Edge{Model: gorm.Model{ID: 88}, FromID: 1, ToID: 2, Weight: 2}).Error)
+	assert.NoError(pg.db.Create(&EdgeEdit{EdgeID: 88, UserID: 111, Weight: 2, Type: db.EdgeEditTypeCreate}).Error)
+	voter, creator := db.User{Document: db.Document{Key: "222"}}, db.User{Document: db.Document{Key: "111"}}
+	weightOf := func() float64 {
+		_, err := pg.AggregateDirtyEdges(ctx, 10)
+		assert.NoError(err)
+		edge := Edge{}
+		assert.NoError(pg.db.First(&edge, 88).Error)
+		return edge.Weight
+	}
+
+	assert.Error(pg.RetractEdgeWeightVote(ctx, voter, "88"), "no vote yet")
+	assert.NoError(pg.AddEdgeWeightVote(ctx, voter, "88", 10))
+	assert.Equal(6.0, weightOf())
+	assert.NoError(pg.RetractEdgeWeightVote(ctx, voter, "88"))
+	assert.Equal(2.0, weightOf())
+	assert.Error(pg.RetractEdgeWeightVote(ctx, voter, "88"), "already retracted")
+	votes, err := pg.EdgeVotes(ctx, voter)
+	assert.NoError(err)
+	assert.Empty(votes)
+
+	assert.NoError(pg.AddEdgeWeightVote(ctx, voter, "88", 4))
+	assert.Equal(3.0, weightOf())
+	assert.NoError(pg.RetractEdgeWeightVote(ctx, creator, "88"))
+	assert.Equal(4.0, weightOf(), "the vote of the creator can be retracted, too")
+	votes, err = pg.EdgeVotes(ctx, voter)
+	assert.NoError(err)
+	assert.Len(votes, 1)
+	assert.NoError(pg.RetractEdgeWeightVote(ctx, voter, "88"))
+	assert.Equal(2.0, weightOf(), "retracting the only vote resets the weight to the creation weight")
+	assert.Error(pg.RetractEdgeWeightVote(ctx, voter, "99"), "no such edge")
+}
+
+func TestPostgresDB_EdgeVoteStats(t *testing.T) {
+	pg := setupDB(t)
+	ctx := context.Background()
+	assert := assert.New(t)
+	for _, user := range []User{
+		{Model: gorm.Model{ID: 111}, Username: "asdf", PasswordHash: "000", EMail: "a@b"},
+		{Model: gorm.Model{ID: 222}, Username: "fasd", PasswordHash: "111", EMail: "c@d"},
+		{Model: gorm.Model{ID: 333}, Username: "dfas", PasswordHash: "222", EMail: "e@f"},
+		{Model: gorm.Model{ID: 444}, Username: "sdfa", PasswordHash: "333", EMail: "g@h"},
+	} {
+		assert.NoError(pg.db.Create(&user).Error)
+	}
+	for _, node := range []Node{
+		{Model: gorm.Model{ID: 1}, Description: db.Text{"en": "A"}},
+		{Model: gorm.Model{ID: 2}, Description: db.Text{"en": "B"}},
+	} {
+		assert.NoError(pg.db.Create(&node).Error)
+	}
+	assert.NoError(pg.db.Create(&Edge{Model: gorm.Model{ID: 88}, FromID: 1, ToID: 2, Weight: 2}).Error)
+	assert.NoError(pg.db.Create(&Edge{Model: gorm.Model{ID: 99}, FromID: 2, ToID: 1, Weight: 2}).Error)
+	for _, edit := range []EdgeEdit{
+		{EdgeID: 88, UserID: 111, Weight: 2, Type: db.EdgeEditTypeCreate},
+		{EdgeID: 88, UserID: 222, Weight: 9, Type: db.EdgeEditTypeVote},
+		{EdgeID: 88, UserID: 222, Weight: 2.5, Type: db.EdgeEditTypeVote}, // replaces the vote above
+		{EdgeID: 88, UserID: 333, Weight: 8, Type: db.EdgeEditTypeVote},
+		{EdgeID: 88, UserID: 444, Weight: 1, Type: db.EdgeEditTypeVote},
+		{EdgeID: 88, UserID: 444, Type: db.EdgeEditTypeRetract},
+	} {
+		edit.CreatedAt = TEST_TimeNow
+		assert.NoError(pg.db.Create(&edit).Error)
+	}
+
+	stats, err := pg.EdgeVoteStats(ctx, "88")
+	assert.NoError(err)
+	if assert.NotNil(stats) {
+		assert.Equal(3, stats.Count)
+		assert.InDelta(12.5/3, *stats.Mean, 1e-9)
+		assert.Equal(2.5, *stats.Median)
+		assert.InDelta(2.7182510717166815, *stats.StdDev, 1e-9)
+		assert.Equal([]*model.VoteBucket{
+			{From: 2, To: 3, Count: 2},
+			{From: 8, To: 9, Count: 1},
+		}, stats.Histogram)
+	}
+	stats, err = pg.EdgeVoteStats(ctx, "99")
+	assert.NoError(err)
+	assert.Equal(&model.EdgeVoteStats{Count: 0, Histogram: []*model.VoteBucket{}}, stats)
+	_, err = pg.EdgeVoteStats(ctx, "77")
+	assert.Error(err)
+}
+
+func TestPostgresDB_EdgeVotes(t *testing.T) {
+	pg := setupDB(t)
+	ctx := context.Background()
+	assert := assert.New(t)
+	users := []User{
+		{Username: "voter", PasswordHash: "0", EMail: "a@b"},
+		{Username: "other", PasswordHash: "1", EMail: "c@d"},
+	}
+	for i := range users {
+		assert.NoError(pg.db.Create(&users[i]).Error)
+	}
+	voter := db.User{Document: db.Document{Key: itoa(users[0].ID)}}
+	for _, node := range []Node{{Description: db.Text{"en": "A"}}, {Description: db.Text{"en": "B"}}, {Description: db.Text{"en": "C"}}} {
+		assert.NoError(pg.db.Create(&node).Error)
+	}
+	for _, edge := range []Edge{
+		{FromID: 1, ToID: 2, Weight: 2},
+		{FromID: 2, ToID: 3, Weight: 2},
+		{FromID: 1, ToID: 3, Weight: 2, Model: gorm.Model{DeletedAt: gorm.DeletedAt{Time: TEST_TimeNow, Valid: true}}},
+	} {
+		assert.NoError(pg.db.Create(&edge).Error)
+	}
+	assert.NoError(pg.db.Create(&EdgeEdit{EdgeID: 1, UserID: users[0].ID, Type: db.EdgeEditTypeCreate, Weight: 2}).Error)
+	assert.NoError(pg.db.Create(&EdgeEdit{EdgeID: 3, UserID: users[0].ID, Type: db.EdgeEditTypeCreate, Weight: 2}).Error)
+	assert.NoError(pg.db.Create(&EdgeEdit{EdgeID: 2, UserID: users[1].ID, Type: db.EdgeEditTypeCreate, Weight: 2}).Error)
+	assert.NoError(pg.AddEdgeWeightVote(ctx, voter, "1", 7))
+	assert.NoError(pg.AddEdgeWeightVote(ctx, voter, "1", 4))
+
+	votes, err := pg.EdgeVotes(ctx, voter)
+	assert.NoError(err)
+	if assert.Len(votes, 1, "only the latest vote on active edges") {
+		assert.Equal("1", votes[0].EdgeID)
+		assert.Equal(4.0, votes[0].Weight)
+	}
+	votes, err = pg.EdgeVotes(ctx, db.User{Document: db.Document{Key: itoa(users[1].ID)}})
+	assert.NoError(err)
+	if assert.Len(votes, 1) {
+		assert.Equal("2", votes[0].EdgeID)
+	}
+}
@@ -25,7 +25,14 @@ func TESTONLY_SetupAndCleanup(t *testing.T) *PostgresDB {
 	pg.db.Exec(`DROP TABLE IF EXISTS users CASCADE`)
 	pg.db.Exec(`DROP TABLE IF EXISTS edge_edits CASCADE`)
 	pg.db.Exec(`DROP TABLE IF EXISTS edges CASCADE`)
+	pg.db.Exec(`DROP TABLE IF EXISTS resource_votes CASCADE`)
+	pg.db.Exec(`DROP TABLE IF EXISTS resource_edits CASCADE`)
+	pg.db.Exec(`DROP TABLE IF EXISTS resources CASCADE`)
+	pg.db.Exec(`DROP TABLE IF EXISTS node_redirects CASCADE`)
+	pg.db.Exec(`DROP TABLE IF EXISTS edge_redirects CASCADE`)
 	pg.db.Exec(`DROP TABLE IF EXISTS node_edits CASCADE`)
+	pg.db.Exec(`DROP TABLE IF EXISTS node_tags CASCADE`)
+	pg.db.Exec(`DROP TABLE IF EXISTS tags CASCADE`)
 	pg.db.Exec(`DROP TABLE IF EXISTS nodes CASCADE`)
 	pg.db.Exec(`DROP TABLE IF EXISTS roles CASCADE`)
 	pg.db.Exec(`DROP INDEX IF EXISTS idx_nodes_description_text_trgm;`)
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/suxatcode/learn-graph-poc-backend/db"
+	"github.com/suxatcode/learn-graph-poc-backend/graph/model"
 )
 
 func TestMakeStringToken(t *testing.T) {
@@ -13,6 +15,162 @@ func TestMakeStringToken(t *testing.T) {
 	assert.True(len(token) >= (AUTH_TOKEN_LENGTH * 4 / 3))
 }
 
+func TestRemoveTranslation(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Node     Node
+		Language string
+		Field    model.TranslationField
+		ExpNode  Node
+		ExpError bool
+	}{
+		{
+			Name:     "description",
+			Node:     Node{Description: db.Text{"en": "A", "zh": "A"}, Resources: db.Text{"zh": "B"}},
+			Language: "zh",
+			Field:    model.TranslationFieldDescription,
+			ExpNode:  Node{Description: db.Text{"en": "A"}, Resources: db.Text{"zh": "B"}},
+		},
+		{
+			Name:     "resources",
+			Node:     Node{Description: db.Text{"zh": "A"}, Resources: db.Text{"zh": "B"}},
+			Language: "zh",
+			Field:    model.TranslationFieldResources,
+			ExpNode:  Node{Description: db.Text{"zh": "A"}, Resources: db.Text{}},
+		},
+		{
+			Name:     "only description",
+			Node:     Node{Description: db.Text{"zh": "A"}},
+			Language: "zh",
+			Field:    model.TranslationFieldDescription,
+			ExpError: true,
+		},
+		{
+			Name:     "missing language",
+			Node:     Node{Description: db.Text{"zh": "A"}},
+			Language: "en",
+			Field:    model.TranslationFieldResources,
+			ExpError: true,
+		},
+		{
+			Name:     "field of resources",
+			Node:     Node{Description: db.Text{"zh": "A", "en": "A"}},
+			Language: "en",
+			Field:    model.TranslationFieldTitle,
+			ExpError: true,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			err := removeTranslation(&test.Node, test.Language, test.Field)
+			if test.ExpError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.ExpNode, test.Node)
+		})
+	}
+}
+
+func TestMoveTranslation(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Node     Node
+		From, To string
+		ExpNode  Node
+		ExpError bool
+	}{
+		{
+			Name:    "description and resources",
+			Node:    Node{Description: db.Text{"zh": "Apple", "de": "Apfel"}, Resources: db.Text{"zh": "fruit"}},
+			From:    "zh",
+			To:      "en",
+			ExpNode: Node{Description: db.Text{"en": "Apple", "de": "Apfel"}, Resources: db.Text{"en": "fruit"}},
+		},
+		{
+			Name:    "only description",
+			Node:    Node{Description: db.Text{"zh": "Apple"}},
+			From:    "zh",
+			To:      "en",
+			ExpNode: Node{Description: db.Text{"en": "Apple"}},
+		},
+		{
+			Name:     "target exists",
+			Node:     Node{Description: db.Text{"zh": "Apple"}, Resources: db.Text{"en": "fruit"}},
+			From:     "zh",
+			To:       "en",
+			ExpError: true,
+		},
+		{
+			Name:     "source missing",
+			Node:     Node{Description: db.Text{"de": "Apfel"}},
+			From:     "zh",
+			To:       "en",
+			ExpError: true,
+		},
+		{
+			Name:     "same language",
+			Node:     Node{Description: db.Text{"de": "Apfel"}},
+			From:     "de",
+			To:       "de",
+			ExpError: true,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			err := moveTranslation(&test.Node, test.From, test.To)
+			if test.ExpError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.ExpNode, test.Node)
+		})
+	}
+}
+
 func strptr(s string) *string {
 	return &s
 }
+
+func TestVerifyResourceInput(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		URL      string
+		ExpError bool
+	}{
+		{Name: "https", URL: "https://example.com/book"},
+		{Name: "http", URL: "http://example.com/book?page=2"},
+		{Name: "not a url", URL: "not a url", ExpError: true},
+		{Name: "javascript", URL: "javascript:alert(1)", ExpError: true},
+		{Name: "data", URL: "data:text/html,<script>alert(1)</script>", ExpError: true},
+		{Name: "relative", URL: "/relative", ExpError: true},
+		{Name: "ftp", URL: "ftp://x", ExpError: true},
+		{Name: "no host", URL: "https:///path", ExpError: true},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			err := verifyResourceInput(model.ResourceInput{URL: test.URL, Kind: model.ResourceKindBook, Language: "en"})
+			if test.ExpError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSnippetToHTML(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("<b>Plants</b> &amp; sugar", snippetToHTML(headlineStartSel+"Plants"+headlineStopSel+" & sugar"))
+	assert.Equal("&lt;img src=x onerror=alert(1)&gt; <b>plant</b>", snippetToHTML("<img src=x onerror=alert(1)> "+headlineStartSel+"plant"+headlineStopSel))
+}
+
+func TestVerifyLanguages(t *testing.T) {
+	assert := assert.New(t)
+	text := func(lang string) *model.Text {
+		return &model.Text{Translations: []*model.Translation{{Language: lang, Content: "A"}}}
+	}
+	assert.NoError(verifyLanguages(text("en"), nil))
+	assert.NoError(verifyLanguages(text("de"), text("zh")))
+	assert.Error(verifyLanguages(text("xx")))
+	assert.Error(verifyLanguages(text("en"), text("abc-zzzzzzzz")))
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/suxatcode/learn-graph-poc-backend/db"
 )
@@ -15,6 +16,14 @@ func atoi(s string) uint {
 	return i
 }
 
+func atoiAll(s []string) []uint {
+	r := make([]uint, 0, len(s))
+	for _, v := range s {
+		r = append(r, atoi(v))
+	}
+	return r
+}
+
 func itoa(i uint) string {
 	return fmt.Sprint(i)
 }
@@ -32,3 +41,13 @@ func mergeText(a, b db.Text) db.Text {
 	}
 	return r
 }
+
+// languagesOf returns the languages of a text in sorted order.
+func languagesOf(text db.Text) []string {
+	languages := make([]string, 0, len(text))
+	for lang := range text {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	return languages
+}
@@ -0,0 +1,31 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.44
+
+import (
+	"context"
+
+	"github.com/suxatcode/learn-graph-poc-backend/graph/generated"
+	"github.com/suxatcode/learn-graph-poc-backend/graph/model"
+)
+
+// MyVote is the resolver for the myVote field.
+func (r *edgeResolver) MyVote(ctx context.Context, obj *model.Edge) (*float64, error) {
+	return r.Ctrl.MyVote(ctx, obj.ID)
+}
+
+// LearningResources is the resolver for the learningResources field.
+func (r *nodeResolver) LearningResources(ctx context.Context, obj *model.Node, kind *model.ResourceKind) ([]*model.Resource, error) {
+	return r.Ctrl.NodeResources(ctx, obj.ID, kind)
+}
+
+// Edge returns generated.EdgeResolver implementation.
+func (r *Resolver) Edge() generated.EdgeResolver { return &edgeResolver{r} }
+
+// Node returns generated.NodeResolver implementation.
+func (r *Resolver) Node() generated.NodeResolver { return &nodeResolver{r} }
+
+type edgeResolver struct{ *Resolver }
+type nodeResolver struct{ *Resolver }
@@ -18,25 +18,65 @@ type CreateUserResult struct {
 	Login *LoginResult `json:"login"`
 }
 
+type DeletedEdge struct {
+	Edge      *Edge     `json:"edge"`
+	DeletedAt time.Time `json:"deletedAt"`
+	DeletedBy *string   `json:"deletedBy,omitempty"`
+}
+
+type DeletedNode struct {
+	Node      *Node     `json:"node"`
+	DeletedAt time.Time `json:"deletedAt"`
+	DeletedBy *string   `json:"deletedBy,omitempty"`
+}
+
+type DiffChunk struct {
+	Operation DiffOperation `json:"operation"`
+	Text      string        `json:"text"`
+}
+
 type Edge struct {
-	ID     string  `json:"id"`
-	From   string  `json:"from"`
-	To     string  `json:"to"`
-	Weight float64 `json:"weight"`
+	ID     string   `json:"id"`
+	From   string   `json:"from"`
+	To     string   `json:"to"`
+	Weight float64  `json:"weight"`
+	Type   EdgeType `json:"type"`
+	MyVote *float64 `json:"myVote,omitempty"`
 }
 
 type EdgeEdit struct {
 	Username  string       `json:"username"`
 	Type      EdgeEditType `json:"type"`
+	EdgeType  *EdgeType    `json:"edgeType,omitempty"`
 	UpdatedAt time.Time    `json:"updatedAt"`
 	Weight    float64      `json:"weight"`
 }
 
+type EdgeVote struct {
+	EdgeID    string    `json:"edgeID"`
+	Weight    float64   `json:"weight"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type EdgeVoteStats struct {
+	Count     int           `json:"count"`
+	Mean      *float64      `json:"mean,omitempty"`
+	Median    *float64      `json:"median,omitempty"`
+	StdDev    *float64      `json:"stdDev,omitempty"`
+	Histogram []*VoteBucket `json:"histogram"`
+}
+
 type Graph struct {
 	Nodes []*Node `json:"nodes,omitempty"`
 	Edges []*Edge `json:"edges,omitempty"`
 }
 
+type GraphChange struct {
+	Type GraphChangeType `json:"type"`
+	ID   string          `json:"id"`
+	Time time.Time       `json:"time"`
+}
+
 type LoginAuthentication struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
@@ -54,25 +94,79 @@ type Mutation struct {
 }
 
 type Node struct {
-	ID          string  `json:"id"`
-	Description string  `json:"description"`
-	Resources   *string `json:"resources,omitempty"`
-	Position    *Vector `json:"position,omitempty"`
+	ID                string            `json:"id"`
+	Description       string            `json:"description"`
+	Resources         *string           `json:"resources,omitempty"`
+	Position          *Vector           `json:"position,omitempty"`
+	Tags              []*Tag            `json:"tags,omitempty"`
+	LearningResources []*Resource       `json:"learningResources"`
+	MatchedLanguage   *string           `json:"matchedLanguage,omitempty"`
+	Translations      []*TranslationOut `json:"translations"`
 }
 
 type NodeEdit struct {
-	Username       string       `json:"username"`
-	Type           NodeEditType `json:"type"`
-	NewDescription string       `json:"newDescription"`
-	NewResources   *string      `json:"newResources,omitempty"`
-	UpdatedAt      time.Time    `json:"updatedAt"`
+	ID             string            `json:"id"`
+	Username       string            `json:"username"`
+	Type           NodeEditType      `json:"type"`
+	NewDescription string            `json:"newDescription"`
+	NewResources   *string           `json:"newResources,omitempty"`
+	UpdatedAt      time.Time         `json:"updatedAt"`
+	Tag            *Tag              `json:"tag,omitempty"`
+	Translations   []*TranslationOut `json:"translations"`
 }
 
 type Query struct {
 }
 
+type Resource struct {
+	ID              string            `json:"id"`
+	URL             string            `json:"url"`
+	Title           string            `json:"title"`
+	Kind            ResourceKind      `json:"kind"`
+	Language        string            `json:"language"`
+	DurationMinutes *int              `json:"durationMinutes,omitempty"`
+	Votes           int               `json:"votes"`
+	Translations    []*TranslationOut `json:"translations"`
+}
+
+type ResourceEdit struct {
+	ID              string           `json:"id"`
+	Username        string           `json:"username"`
+	Type            ResourceEditType `json:"type"`
+	URL             string           `json:"url"`
+	Title           string           `json:"title"`
+	Kind            ResourceKind     `json:"kind"`
+	Language        string           `json:"language"`
+	DurationMinutes *int             `json:"durationMinutes,omitempty"`
+	UpdatedAt       time.Time        `json:"updatedAt"`
+}
+
+type ResourceInput struct {
+	URL             string       `json:"url"`
+	Title           *Text        `json:"title"`
+	Kind            ResourceKind `json:"kind"`
+	Language        string       `json:"language"`
+	DurationMinutes *int         `json:"durationMinutes,omitempty"`
+}
+
+type SearchHit struct {
+	Node               *Node   `json:"node"`
+	Rank               float64 `json:"rank"`
+	DescriptionSnippet string  `json:"descriptionSnippet"`
+	ResourcesSnippet   *string `json:"resourcesSnippet,omitempty"`
+}
+
 type Status struct {
-	Message string `json:"Message"`
+	Message    string  `json:"Message"`
+	Duplicates []*Node `json:"duplicates,omitempty"`
+}
+
+type Subscription struct {
+}
+
+type Tag struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
 }
 
 type Text struct {
@@ -84,27 +178,150 @@ type Translation struct {
 	Content  string `json:"content"`
 }
 
+type TranslationCoverage struct {
+	Language     string `json:"language"`
+	Descriptions int    `json:"descriptions"`
+	Resources    int    `json:"resources"`
+	Total        int    `json:"total"`
+}
+
+type TranslationDiff struct {
+	Language    string       `json:"language"`
+	Description []*DiffChunk `json:"description"`
+	Resources   []*DiffChunk `json:"resources"`
+}
+
+type TranslationFilter struct {
+	NodeIDs  []string `json:"nodeIDs,omitempty"`
+	EditedBy *string  `json:"editedBy,omitempty"`
+	Matches  *string  `json:"matches,omitempty"`
+}
+
+type TranslationOut struct {
+	Language string           `json:"language"`
+	Field    TranslationField `json:"field"`
+	Content  string           `json:"content"`
+}
+
 type Vector struct {
 	X float64 `json:"x"`
 	Y float64 `json:"y"`
 	Z float64 `json:"z"`
 }
 
+type VoteBucket struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Count int     `json:"count"`
+}
+
+type DiffOperation string
+
+const (
+	DiffOperationEqual  DiffOperation = "equal"
+	DiffOperationInsert DiffOperation = "insert"
+	DiffOperationDelete DiffOperation = "delete"
+)
+
+var AllDiffOperation = []DiffOperation{
+	DiffOperationEqual,
+	DiffOperationInsert,
+	DiffOperationDelete,
+}
+
+func (e DiffOperation) IsValid() bool {
+	switch e {
+	case DiffOperationEqual, DiffOperationInsert, DiffOperationDelete:
+		return true
+	}
+	return false
+}
+
+func (e DiffOperation) String() string {
+	return string(e)
+}
+
+func (e *DiffOperation) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DiffOperation(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DiffOperation", str)
+	}
+	return nil
+}
+
+func (e DiffOperation) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type Direction string
+
+const (
+	DirectionIn   Direction = "IN"
+	DirectionOut  Direction = "OUT"
+	DirectionBoth Direction = "BOTH"
+)
+
+var AllDirection = []Direction{
+	DirectionIn,
+	DirectionOut,
+	DirectionBoth,
+}
+
+func (e Direction) IsValid() bool {
+	switch e {
+	case DirectionIn, DirectionOut, DirectionBoth:
+		return true
+	}
+	return false
+}
+
+func (e Direction) String() string {
+	return string(e)
+}
+
+func (e *Direction) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Direction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Direction", str)
+	}
+	return nil
+}
+
+func (e Direction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type EdgeEditType string
 
 const (
-	EdgeEditTypeCreate EdgeEditType = "create"
-	EdgeEditTypeEdit   EdgeEditType = "edit"
+	EdgeEditTypeCreate  EdgeEditType = "create"
+	EdgeEditTypeEdit    EdgeEditType = "edit"
+	EdgeEditTypeDelete  EdgeEditType = "delete"
+	EdgeEditTypeRestore EdgeEditType = "restore"
+	EdgeEditTypeRetract EdgeEditType = "retract"
 )
 
 var AllEdgeEditType = []EdgeEditType{
 	EdgeEditTypeCreate,
 	EdgeEditTypeEdit,
+	EdgeEditTypeDelete,
+	EdgeEditTypeRestore,
+	EdgeEditTypeRetract,
 }
 
 func (e EdgeEditType) IsValid() bool {
 	switch e {
-	case EdgeEditTypeCreate, EdgeEditTypeEdit:
+	case EdgeEditTypeCreate, EdgeEditTypeEdit, EdgeEditTypeDelete, EdgeEditTypeRestore, EdgeEditTypeRetract:
 		return true
 	}
 	return false
@@ -131,21 +348,131 @@ func (e EdgeEditType) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+type EdgeType string
+
+const (
+	EdgeTypePrerequisite EdgeType = "prerequisite"
+	EdgeTypeRelated      EdgeType = "related"
+	EdgeTypePartOf       EdgeType = "partOf"
+	EdgeTypeExampleOf    EdgeType = "exampleOf"
+)
+
+var AllEdgeType = []EdgeType{
+	EdgeTypePrerequisite,
+	EdgeTypeRelated,
+	EdgeTypePartOf,
+	EdgeTypeExampleOf,
+}
+
+func (e EdgeType) IsValid() bool {
+	switch e {
+	case EdgeTypePrerequisite, EdgeTypeRelated, EdgeTypePartOf, EdgeTypeExampleOf:
+		return true
+	}
+	return false
+}
+
+func (e EdgeType) String() string {
+	return string(e)
+}
+
+func (e *EdgeType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EdgeType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EdgeType", str)
+	}
+	return nil
+}
+
+func (e EdgeType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type GraphChangeType string
+
+const (
+	GraphChangeTypeNodeCreated GraphChangeType = "nodeCreated"
+	GraphChangeTypeNodeEdited  GraphChangeType = "nodeEdited"
+	GraphChangeTypeNodeDeleted GraphChangeType = "nodeDeleted"
+	GraphChangeTypeEdgeCreated GraphChangeType = "edgeCreated"
+	GraphChangeTypeEdgeVoted   GraphChangeType = "edgeVoted"
+	GraphChangeTypeEdgeDeleted GraphChangeType = "edgeDeleted"
+)
+
+var AllGraphChangeType = []GraphChangeType{
+	GraphChangeTypeNodeCreated,
+	GraphChangeTypeNodeEdited,
+	GraphChangeTypeNodeDeleted,
+	GraphChangeTypeEdgeCreated,
+	GraphChangeTypeEdgeVoted,
+	GraphChangeTypeEdgeDeleted,
+}
+
+func (e GraphChangeType) IsValid() bool {
+	switch e {
+	case GraphChangeTypeNodeCreated, GraphChangeTypeNodeEdited, GraphChangeTypeNodeDeleted, GraphChangeTypeEdgeCreated, GraphChangeTypeEdgeVoted, GraphChangeTypeEdgeDeleted:
+		return true
+	}
+	return false
+}
+
+func (e GraphChangeType) String() string {
+	return string(e)
+}
+
+func (e *GraphChangeType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GraphChangeType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GraphChangeType", str)
+	}
+	return nil
+}
+
+func (e GraphChangeType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type NodeEditType string
 
 const (
-	NodeEditTypeCreate NodeEditType = "create"
-	NodeEditTypeEdit   NodeEditType = "edit"
+	NodeEditTypeCreate            NodeEditType = "create"
+	NodeEditTypeEdit              NodeEditType = "edit"
+	NodeEditTypeRevert            NodeEditType = "revert"
+	NodeEditTypeDelete            NodeEditType = "delete"
+	NodeEditTypeRestore           NodeEditType = "restore"
+	NodeEditTypeTag               NodeEditType = "tag"
+	NodeEditTypeUntag             NodeEditType = "untag"
+	NodeEditTypeMerge             NodeEditType = "merge"
+	NodeEditTypeRemoveTranslation NodeEditType = "removeTranslation"
+	NodeEditTypeMoveTranslation   NodeEditType = "moveTranslation"
 )
 
 var AllNodeEditType = []NodeEditType{
 	NodeEditTypeCreate,
 	NodeEditTypeEdit,
+	NodeEditTypeRevert,
+	NodeEditTypeDelete,
+	NodeEditTypeRestore,
+	NodeEditTypeTag,
+	NodeEditTypeUntag,
+	NodeEditTypeMerge,
+	NodeEditTypeRemoveTranslation,
+	NodeEditTypeMoveTranslation,
 }
 
 func (e NodeEditType) IsValid() bool {
 	switch e {
-	case NodeEditTypeCreate, NodeEditTypeEdit:
+	case NodeEditTypeCreate, NodeEditTypeEdit, NodeEditTypeRevert, NodeEditTypeDelete, NodeEditTypeRestore, NodeEditTypeTag, NodeEditTypeUntag, NodeEditTypeMerge, NodeEditTypeRemoveTranslation, NodeEditTypeMoveTranslation:
 		return true
 	}
 	return false
@@ -171,3 +498,134 @@ func (e *NodeEditType) UnmarshalGQL(v interface{}) error {
 func (e NodeEditType) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
+
+type ResourceEditType string
+
+const (
+	ResourceEditTypeCreate ResourceEditType = "create"
+	ResourceEditTypeEdit   ResourceEditType = "edit"
+	ResourceEditTypeDelete ResourceEditType = "delete"
+)
+
+var AllResourceEditType = []ResourceEditType{
+	ResourceEditTypeCreate,
+	ResourceEditTypeEdit,
+	ResourceEditTypeDelete,
+}
+
+func (e ResourceEditType) IsValid() bool {
+	switch e {
+	case ResourceEditTypeCreate, ResourceEditTypeEdit, ResourceEditTypeDelete:
+		return true
+	}
+	return false
+}
+
+func (e ResourceEditType) String() string {
+	return string(e)
+}
+
+func (e *ResourceEditType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ResourceEditType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ResourceEditType", str)
+	}
+	return nil
+}
+
+func (e ResourceEditType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ResourceKind string
+
+const (
+	ResourceKindVideo    ResourceKind = "video"
+	ResourceKindBook     ResourceKind = "book"
+	ResourceKindArticle  ResourceKind = "article"
+	ResourceKindExercise ResourceKind = "exercise"
+)
+
+var AllResourceKind = []ResourceKind{
+	ResourceKindVideo,
+	ResourceKindBook,
+	ResourceKindArticle,
+	ResourceKindExercise,
+}
+
+func (e ResourceKind) IsValid() bool {
+	switch e {
+	case ResourceKindVideo, ResourceKindBook, ResourceKindArticle, ResourceKindExercise:
+		return true
+	}
+	return false
+}
+
+func (e ResourceKind) String() string {
+	return string(e)
+}
+
+func (e *ResourceKind) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ResourceKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ResourceKind", str)
+	}
+	return nil
+}
+
+func (e ResourceKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type TranslationField string
+
+const (
+	TranslationFieldDescription TranslationField = "description"
+	TranslationFieldResources   TranslationField = "resources"
+	TranslationFieldTitle       TranslationField = "title"
+)
+
+var AllTranslationField = []TranslationField{
+	TranslationFieldDescription,
+	TranslationFieldResources,
+	TranslationFieldTitle,
+}
+
+func (e TranslationField) IsValid() bool {
+	switch e {
+	case TranslationFieldDescription, TranslationFieldResources, TranslationFieldTitle:
+		return true
+	}
+	return false
+}
+
+func (e TranslationField) String() string {
+	return string(e)
+}
+
+func (e *TranslationField) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TranslationField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TranslationField", str)
+	}
+	return nil
+}
+
+func (e TranslationField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
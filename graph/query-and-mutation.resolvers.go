@@ -14,13 +14,13 @@ import (
 )
 
 // CreateNode is the resolver for the createNode field.
-func (r *mutationResolver) CreateNode(ctx context.Context, description model.Text, resources *model.Text) (*model.CreateEntityResult, error) {
-	return r.Ctrl.CreateNode(ctx, description, resources)
+func (r *mutationResolver) CreateNode(ctx context.Context, description model.Text, resources *model.Text, force bool) (*model.CreateEntityResult, error) {
+	return r.Ctrl.CreateNode(ctx, description, resources, force)
 }
 
 // CreateEdge is the resolver for the createEdge field.
-func (r *mutationResolver) CreateEdge(ctx context.Context, from string, to string, weight float64) (*model.CreateEntityResult, error) {
-	return r.Ctrl.CreateEdge(ctx, from, to, weight)
+func (r *mutationResolver) CreateEdge(ctx context.Context, from string, to string, weight float64, typeArg model.EdgeType) (*model.CreateEntityResult, error) {
+	return r.Ctrl.CreateEdge(ctx, from, to, weight, typeArg)
 }
 
 // EditNode is the resolver for the editNode field.
@@ -28,11 +28,76 @@ func (r *mutationResolver) EditNode(ctx context.Context, id string, description
 	return r.Ctrl.EditNode(ctx, id, description, resources)
 }
 
+// RevertNode is the resolver for the revertNode field.
+func (r *mutationResolver) RevertNode(ctx context.Context, id string, toEdit string) (*model.Status, error) {
+	return r.Ctrl.RevertNode(ctx, id, toEdit)
+}
+
 // SubmitVote is the resolver for the submitVote field.
 func (r *mutationResolver) SubmitVote(ctx context.Context, id string, value float64) (*model.Status, error) {
 	return r.Ctrl.SubmitVote(ctx, id, value)
 }
 
+// RetractVote is the resolver for the retractVote field.
+func (r *mutationResolver) RetractVote(ctx context.Context, edgeID string) (*model.Status, error) {
+	return r.Ctrl.RetractVote(ctx, edgeID)
+}
+
+// CreateTag is the resolver for the createTag field.
+func (r *mutationResolver) CreateTag(ctx context.Context, name model.Text) (*model.CreateEntityResult, error) {
+	return r.Ctrl.CreateTag(ctx, name)
+}
+
+// RemoveTranslation is the resolver for the removeTranslation field.
+func (r *mutationResolver) RemoveTranslation(ctx context.Context, nodeID string, language string, field model.TranslationField) (*model.Status, error) {
+	return r.Ctrl.RemoveTranslation(ctx, nodeID, language, field)
+}
+
+// MoveTranslation is the resolver for the moveTranslation field.
+func (r *mutationResolver) MoveTranslation(ctx context.Context, nodeID string, fromLanguage string, toLanguage string) (*model.Status, error) {
+	return r.Ctrl.MoveTranslation(ctx, nodeID, fromLanguage, toLanguage)
+}
+
+// MoveTranslations is the resolver for the moveTranslations field.
+func (r *mutationResolver) MoveTranslations(ctx context.Context, filter model.TranslationFilter, fromLanguage string, toLanguage string) ([]string, error) {
+	return r.Ctrl.MoveTranslations(ctx, filter, fromLanguage, toLanguage)
+}
+
+// RemoveTranslations is the resolver for the removeTranslations field.
+func (r *mutationResolver) RemoveTranslations(ctx context.Context, filter model.TranslationFilter, language string, field model.TranslationField) ([]string, error) {
+	return r.Ctrl.RemoveTranslations(ctx, filter, language, field)
+}
+
+// TagNode is the resolver for the tagNode field.
+func (r *mutationResolver) TagNode(ctx context.Context, nodeID string, tagID string) (*model.Status, error) {
+	return r.Ctrl.TagNode(ctx, nodeID, tagID)
+}
+
+// UntagNode is the resolver for the untagNode field.
+func (r *mutationResolver) UntagNode(ctx context.Context, nodeID string, tagID string) (*model.Status, error) {
+	return r.Ctrl.UntagNode(ctx, nodeID, tagID)
+}
+
+// CreateResource is the resolver for the createResource field.
+func (r *mutationResolver) CreateResource(ctx context.Context, nodeID string, resource model.ResourceInput) (*model.CreateEntityResult, error) {
+	return r.Ctrl.CreateResource(ctx, nodeID, resource)
+}
+
+// EditResource is the resolver for the editResource field.
+func (r *mutationResolver) EditResource(ctx context.Context, id string, resource model.ResourceInput) (*model.Status, error) {
+	return r.Ctrl.EditResource(ctx, id, resource)
+}
+
+// DeleteResource is the resolver for the deleteResource field.
+func (r *mutationResolver) DeleteResource(ctx context.Context, id string) (*model.Status, error) {
+	return r.Ctrl.DeleteResource(ctx, id)
+}
+
+// VoteResource is the resolver for the voteResource field.
+func (r *mutationResolver) VoteResource(ctx context.Context, id string) (*model.Status, error) {
+	return r.Ctrl.VoteResource(ctx, id)
+}
+
 // DeleteNode is the resolver for the deleteNode field.
 func (r *mutationResolver) DeleteNode(ctx context.Context, id string) (*model.Status, error) {
 	return r.Ctrl.DeleteNode(ctx, id)
@@ -43,6 +108,21 @@ func (r *mutationResolver) DeleteEdge(ctx context.Context, id string) (*model.St
 	return r.Ctrl.DeleteEdge(ctx, id)
 }
 
+// MergeNodes is the resolver for the mergeNodes field.
+func (r *mutationResolver) MergeNodes(ctx context.Context, keep string, remove string) (*model.Status, error) {
+	return r.Ctrl.MergeNodes(ctx, keep, remove)
+}
+
+// RestoreNode is the resolver for the restoreNode field.
+func (r *mutationResolver) RestoreNode(ctx context.Context, id string) (*model.Status, error) {
+	return r.Ctrl.RestoreNode(ctx, id)
+}
+
+// RestoreEdge is the resolver for the restoreEdge field.
+func (r *mutationResolver) RestoreEdge(ctx context.Context, id string) (*model.Status, error) {
+	return r.Ctrl.RestoreEdge(ctx, id)
+}
+
 // CreateUserWithEMail is the resolver for the createUserWithEMail field.
 func (r *mutationResolver) CreateUserWithEMail(ctx context.Context, username string, password string, email string) (*model.CreateUserResult, error) {
 	result, err := r.Db.CreateUserWithEMail(ctx, username, password, email)
@@ -98,13 +178,13 @@ func (r *mutationResolver) DeleteAccount(ctx context.Context) (*model.Status, er
 }
 
 // Graph is the resolver for the graph field.
-func (r *queryResolver) Graph(ctx context.Context) (*model.Graph, error) {
-	return r.Ctrl.Graph(ctx)
+func (r *queryResolver) Graph(ctx context.Context, edgeTypes []model.EdgeType, tags []string) (*model.Graph, error) {
+	return r.Ctrl.Graph(ctx, edgeTypes, tags)
 }
 
 // Resources is the resolver for the resources field.
 func (r *queryResolver) Resources(ctx context.Context, nodeID string) (*model.Node, error) {
-	node, err := r.Db.Node(ctx, nodeID)
+	node, err := r.Ctrl.Node(ctx, nodeID)
 	if err != nil {
 		log.Ctx(ctx).Error().Msgf("%v", err)
 	}
@@ -122,9 +202,79 @@ func (r *queryResolver) EdgeEdits(ctx context.Context, edgeID string) ([]*model.
 	return r.Ctrl.EdgeEdits(ctx, edgeID)
 }
 
+// ResourceEdits is the resolver for the resourceEdits field.
+func (r *queryResolver) ResourceEdits(ctx context.Context, resourceID string) ([]*model.ResourceEdit, error) {
+	return r.Ctrl.ResourceEdits(ctx, resourceID)
+}
+
+// NodeEditDiff is the resolver for the nodeEditDiff field.
+func (r *queryResolver) NodeEditDiff(ctx context.Context, nodeID string, fromEdit string, toEdit string) ([]*model.TranslationDiff, error) {
+	return r.Ctrl.NodeEditDiff(ctx, nodeID, fromEdit, toEdit)
+}
+
 // NodeCompletion is the resolver for the nodeCompletion field.
-func (r *queryResolver) NodeCompletion(ctx context.Context, substring string) ([]*model.Node, error) {
-	return r.Ctrl.NodeCompletion(ctx, substring)
+func (r *queryResolver) NodeCompletion(ctx context.Context, substring string, tags []string) ([]*model.Node, error) {
+	return r.Ctrl.NodeCompletion(ctx, substring, tags)
+}
+
+// Search is the resolver for the search field.
+func (r *queryResolver) Search(ctx context.Context, query string, language *string, limit *int, offset *int) ([]*model.SearchHit, error) {
+	return r.Ctrl.Search(ctx, query, language, limit, offset)
+}
+
+// Tags is the resolver for the tags field.
+func (r *queryResolver) Tags(ctx context.Context) ([]*model.Tag, error) {
+	return r.Ctrl.Tags(ctx)
+}
+
+// TranslationCoverage is the resolver for the translationCoverage field.
+func (r *queryResolver) TranslationCoverage(ctx context.Context) ([]*model.TranslationCoverage, error) {
+	return r.Ctrl.TranslationCoverage(ctx)
+}
+
+// UntranslatedNodes is the resolver for the untranslatedNodes field.
+func (r *queryResolver) UntranslatedNodes(ctx context.Context, language string, first *int, after *string) ([]*model.Node, error) {
+	return r.Ctrl.UntranslatedNodes(ctx, language, first, after)
+}
+
+// LearningPath is the resolver for the learningPath field.
+func (r *queryResolver) LearningPath(ctx context.Context, from string, to string) (*model.Graph, error) {
+	return r.Ctrl.LearningPath(ctx, from, to)
+}
+
+// Prerequisites is the resolver for the prerequisites field.
+func (r *queryResolver) Prerequisites(ctx context.Context, nodeID string, maxDepth *int, minWeight *float64) (*model.Graph, error) {
+	return r.Ctrl.Prerequisites(ctx, nodeID, maxDepth, minWeight)
+}
+
+// Subgraph is the resolver for the subgraph field.
+func (r *queryResolver) Subgraph(ctx context.Context, center string, depth int, direction model.Direction) (*model.Graph, error) {
+	return r.Ctrl.Subgraph(ctx, center, depth, direction)
+}
+
+// GraphInViewport is the resolver for the graphInViewport field.
+func (r *queryResolver) GraphInViewport(ctx context.Context, minX float64, minY float64, maxX float64, maxY float64, zoom *float64) (*model.Graph, error) {
+	return r.Ctrl.GraphInViewport(ctx, minX, minY, maxX, maxY, zoom)
+}
+
+// MyVotes is the resolver for the myVotes field.
+func (r *queryResolver) MyVotes(ctx context.Context) ([]*model.EdgeVote, error) {
+	return r.Ctrl.MyVotes(ctx)
+}
+
+// EdgeVoteStats is the resolver for the edgeVoteStats field.
+func (r *queryResolver) EdgeVoteStats(ctx context.Context, edgeID string) (*model.EdgeVoteStats, error) {
+	return r.Ctrl.EdgeVoteStats(ctx, edgeID)
+}
+
+// DeletedNodes is the resolver for the deletedNodes field.
+func (r *queryResolver) DeletedNodes(ctx context.Context) ([]*model.DeletedNode, error) {
+	return r.Ctrl.DeletedNodes(ctx)
+}
+
+// DeletedEdges is the resolver for the deletedEdges field.
+func (r *queryResolver) DeletedEdges(ctx context.Context) ([]*model.DeletedEdge, error) {
+	return r.Ctrl.DeletedEdges(ctx)
 }
 
 // Mutation returns generated.MutationResolver implementation.
@@ -0,0 +1,32 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.44
+
+import (
+	"context"
+
+	"github.com/suxatcode/learn-graph-poc-backend/graph/generated"
+	"github.com/suxatcode/learn-graph-poc-backend/graph/model"
+)
+
+// GraphChanged is the resolver for the graphChanged field.
+func (r *subscriptionResolver) GraphChanged(ctx context.Context) (<-chan *model.GraphChange, error) {
+	return r.Ctrl.SubscribeGraphChanged(ctx)
+}
+
+// NodeChanged is the resolver for the nodeChanged field.
+func (r *subscriptionResolver) NodeChanged(ctx context.Context, id string) (<-chan *model.Node, error) {
+	return r.Ctrl.SubscribeNodeChanged(ctx, id)
+}
+
+// LayoutUpdated is the resolver for the layoutUpdated field.
+func (r *subscriptionResolver) LayoutUpdated(ctx context.Context) (<-chan *model.Graph, error) {
+	return r.Ctrl.SubscribeLayoutUpdated(ctx)
+}
+
+// Subscription returns generated.SubscriptionResolver implementation.
+func (r *Resolver) Subscription() generated.SubscriptionResolver { return &subscriptionResolver{r} }
+
+type subscriptionResolver struct{ *Resolver }
@@ -6,9 +6,14 @@ import (
 	"os"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/caarlos0/env/v6"
+	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/suxatcode/learn-graph-poc-backend/db"
@@ -28,6 +33,12 @@ type Config struct {
 	LogLevel string `env:"LOGLEVEL" envDefault:"debug"`
 	// HTTP timeouts (read and write)
 	HTTPTimeout time.Duration `env:"TIMEOUT" envDefault:"5s"`
+	// How long deleted nodes and edges are kept in the trash, 0 keeps them
+	// forever.
+	TrashRetention time.Duration `env:"TRASH_RETENTION" envDefault:"720h"`
+	// Origins of browser clients hosted elsewhere, e.g. the frontend, allowed
+	// to open subscriptions.
+	AllowedOrigins []string `env:"ALLOWED_ORIGINS" envSeparator:","`
 }
 
 func GetEnvConfig() Config {
@@ -49,7 +60,7 @@ func RetryAtIntervals(fn func() error, intervals []time.Duration) {
 	}
 }
 
-func graphHandler(conf db.Config) (http.Handler, db.DB) {
+func graphHandler(conf db.Config, trashRetention time.Duration, origins middleware.AllowedOrigins) (http.Handler, db.DB) {
 	var (
 		backend db.DB
 		err     error
@@ -68,12 +79,41 @@ func graphHandler(conf db.Config) (http.Handler, db.DB) {
 	})
 	ctrl := controller.NewController(backend, controller.NewLayouter())
 	go ctrl.PeriodicGraphEmbeddingComputation(context.Background())
-	return middleware.AddAll(handler.NewDefaultServer(
+	go ctrl.PeriodicTrashPurge(context.Background(), trashRetention)
+	go ctrl.PeriodicVoteAggregation(context.Background())
+	srv := newServer(
 		generated.NewExecutableSchema(generated.Config{Resolvers: &graph.Resolver{
 			Db:   backend, /*TODO(skep): to be removed once all calls go through controller*/
 			Ctrl: ctrl,
 		}}),
-	)), backend
+		origins,
+	)
+	srv.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		return next(ctrl.WithLoaders(ctx))
+	})
+	return middleware.AddAll(srv), backend
+}
+
+// newServer is handler.NewDefaultServer, but accepts websocket connections
+// from the allowed origins, too.
+func newServer(es graphql.ExecutableSchema, origins middleware.AllowedOrigins) *handler.Server {
+	srv := handler.New(es)
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		Upgrader: websocket.Upgrader{
+			CheckOrigin:     origins.CheckOrigin,
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+		},
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+	srv.SetQueryCache(lru.New(1000))
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.AutomaticPersistedQuery{Cache: lru.New(100)})
+	return srv
 }
 
 func runGQLServer() {
@@ -99,7 +139,7 @@ func runGQLServer() {
 	}
 	dbconf := db.GetEnvConfig()
 	log.Info().Msgf("Config: %#v", dbconf)
-	graphQLhandler, _ := graphHandler(dbconf)
+	graphQLhandler, _ := graphHandler(dbconf, conf.TrashRetention, conf.AllowedOrigins)
 	handler.Handle("/query", graphQLhandler)
 	server := http.Server{
 		Addr:         ":" + port,
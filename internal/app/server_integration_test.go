@@ -242,7 +242,7 @@ func TestGraphQLHandlers(t *testing.T) {
 		//},
 	} {
 		t.Run(test.Name, func(t *testing.T) {
-			handler, _ := graphHandler(postgres.TESTONLY_Config)
+			handler, _ := graphHandler(postgres.TESTONLY_Config, 0, nil)
 			postgres.TESTONLY_SetupAndCleanup(t)
 			s := httptest.NewServer(handler)
 			defer s.Close()
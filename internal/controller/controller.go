@@ -3,12 +3,20 @@ package controller
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/suxatcode/learn-graph-poc-backend/db"
 	"github.com/suxatcode/learn-graph-poc-backend/graph/model"
+	"github.com/suxatcode/learn-graph-poc-backend/internal/controller/graphalgo"
+	"github.com/suxatcode/learn-graph-poc-backend/internal/controller/textdiff"
+	"github.com/suxatcode/learn-graph-poc-backend/internal/langdetect"
+	"github.com/suxatcode/learn-graph-poc-backend/layout"
 	"github.com/suxatcode/learn-graph-poc-backend/middleware"
+	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
 const (
@@ -25,16 +33,20 @@ type Controller struct {
 	db           db.DB
 	layouter     Layouter
 	graphChanges chan time.Time
+	graphEvents  *broker[*model.GraphChange]
+	layoutEvents *broker[*model.Graph]
 }
 
 func NewController(newdb db.DB, newlayouter Layouter) *Controller {
 	return &Controller{
 		db: newdb, layouter: newlayouter,
 		graphChanges: make(chan time.Time, 1),
+		graphEvents:  newBroker[*model.GraphChange](),
+		layoutEvents: newBroker[*model.Graph](),
 	}
 }
 
-func (c *Controller) CreateNode(ctx context.Context, description model.Text, resources *model.Text) (*model.CreateEntityResult, error) {
+func (c *Controller) CreateNode(ctx context.Context, description model.Text, resources *model.Text, force bool) (*model.CreateEntityResult, error) {
 	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
 	if err != nil || !authenticated || user == nil {
 		if err != nil {
@@ -44,18 +56,635 @@ func (c *Controller) CreateNode(ctx context.Context, description model.Text, res
 		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
 		return AuthNeededForGraphDataChangeResult, ErrAuthNeededForGraphDataChange
 	}
-	id, err := c.db.CreateNode(ctx, *user, &description, resources)
+	notes := append(checkTranslationLanguages(&description), checkTranslationLanguages(resources)...)
+	id, err := c.db.CreateNode(ctx, *user, &description, resources, force)
 	if err != nil {
+		var duplicateErr *db.DuplicateNodesError
+		if errors.As(err, &duplicateErr) {
+			log.Ctx(ctx).Info().Msgf("%v", err)
+			return &model.CreateEntityResult{Status: &model.Status{Message: duplicateErr.Error(), Duplicates: duplicateErr.Duplicates}}, nil
+		}
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	res := &model.CreateEntityResult{ID: id, Status: languageNotesStatus(notes)}
+	c.graphChanged(model.GraphChangeTypeNodeCreated, id)
+	log.Ctx(ctx).Debug().Msgf("CreateNode() -> %v", res)
+	return res, nil
+}
+
+func (c *Controller) CreateEdge(ctx context.Context, from string, to string, weight float64, edgeType model.EdgeType) (*model.CreateEntityResult, error) {
+	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
+	if err != nil || !authenticated || user == nil {
+		if err != nil {
+			log.Ctx(ctx).Error().Msgf("%v", err)
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
+		return AuthNeededForGraphDataChangeResult, ErrAuthNeededForGraphDataChange
+	}
+	if err := c.resolveNodeIDs(ctx, &from, &to); err != nil {
+		return nil, err
+	}
+	ID, err := c.db.CreateEdge(ctx, *user, from, to, weight, db.EdgeType(edgeType))
+	if err != nil {
+		var cycleErr *db.CycleError
+		if errors.As(err, &cycleErr) {
+			log.Ctx(ctx).Info().Msgf("%v", err)
+			return &model.CreateEntityResult{Status: &model.Status{Message: cycleErr.Error()}}, newCycleGQLError(cycleErr)
+		}
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	res := &model.CreateEntityResult{ID: ID}
+	c.graphChanged(model.GraphChangeTypeEdgeCreated, ID)
+	log.Ctx(ctx).Debug().Msgf("CreateEdge() -> %v", res)
+	return res, nil
+}
+
+// newCycleGQLError exposes the node IDs of the cycle to the client as error
+// extension `cycle`.
+func newCycleGQLError(err *db.CycleError) error {
+	return &gqlerror.Error{
+		Err:     err,
+		Message: err.Error(),
+		Extensions: map[string]interface{}{
+			"code":  "EDGE_CREATES_CYCLE",
+			"cycle": err.Cycle,
+		},
+	}
+}
+
+func (c *Controller) EditNode(ctx context.Context, id string, description model.Text, resources *model.Text) (*model.Status, error) {
+	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
+	if err != nil || !authenticated || user == nil {
+		if err != nil {
+			log.Ctx(ctx).Error().Msgf("%v", err)
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
+		return AuthNeededForGraphDataChangeStatus, ErrAuthNeededForGraphDataChange
+	}
+	if err := c.resolveNodeIDs(ctx, &id); err != nil {
+		return nil, err
+	}
+	notes := append(checkTranslationLanguages(&description), checkTranslationLanguages(resources)...)
+	err = c.db.EditNode(ctx, *user, id, &description, resources)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	c.graphChanged(model.GraphChangeTypeNodeEdited, id)
+	status := languageNotesStatus(notes)
+	log.Ctx(ctx).Debug().Msgf("EditNode() -> %v", status)
+	return status, nil
+}
+
+// checkTranslationLanguages returns a note for every translation, whose
+// content does not look like its language. The translations are stored as
+// submitted, since the detection is a heuristic.
+func checkTranslationLanguages(text *model.Text) []string {
+	if text == nil {
+		return nil
+	}
+	notes := []string{}
+	for _, translation := range text.Translations {
+		if translation == nil {
+			continue
+		}
+		check := langdetect.Check(translation.Language, translation.Content)
+		switch {
+		case !check.Mismatch:
+			continue
+		case check.Suggested != "":
+			notes = append(notes, fmt.Sprintf("'%s' looks like '%s', not '%s'", translation.Content, check.Suggested, translation.Language))
+		default:
+			notes = append(notes, fmt.Sprintf("'%s' does not look like '%s'", translation.Content, translation.Language))
+		}
+	}
+	return notes
+}
+
+func languageNotesStatus(notes []string) *model.Status {
+	if len(notes) == 0 {
+		return nil
+	}
+	return &model.Status{Message: strings.Join(notes, "; ")}
+}
+
+func (c *Controller) RevertNode(ctx context.Context, id string, toEdit string) (*model.Status, error) {
+	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
+	if err != nil || !authenticated || user == nil {
+		if err != nil {
+			log.Ctx(ctx).Error().Msgf("%v", err)
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
+		return AuthNeededForGraphDataChangeStatus, ErrAuthNeededForGraphDataChange
+	}
+	if err := c.resolveNodeIDs(ctx, &id); err != nil {
+		return nil, err
+	}
+	edits, err := c.db.NodeEditsByID(ctx, id, []string{toEdit})
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	if !isRevertTarget(edits[0].Type) {
+		err := &InvalidRevertTargetError{EditID: toEdit, Type: edits[0].Type}
+		log.Ctx(ctx).Info().Msgf("%v", err)
+		return nil, err
+	}
+	err = c.db.RevertNode(ctx, *user, id, toEdit)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	c.graphChanged(model.GraphChangeTypeNodeEdited, id)
+	log.Ctx(ctx).Debug().Msgf("RevertNode() -> %v", nil)
+	return nil, nil
+}
+
+// resolveNodeIDs replaces the IDs of merged nodes in place by the IDs of the
+// nodes they were merged into, so that old IDs keep working everywhere.
+func (c *Controller) resolveNodeIDs(ctx context.Context, ids ...*string) error {
+	return resolveIDs(ctx, c.db.ResolveNodeIDs, ids)
+}
+
+// resolveEdgeIDs is resolveNodeIDs for edges folded into other edges.
+func (c *Controller) resolveEdgeIDs(ctx context.Context, ids ...*string) error {
+	return resolveIDs(ctx, c.db.ResolveEdgeIDs, ids)
+}
+
+func resolveIDs(ctx context.Context, resolve func(context.Context, []string) ([]string, error), ids []*string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	unresolved := make([]string, 0, len(ids))
+	for _, id := range ids {
+		unresolved = append(unresolved, *id)
+	}
+	resolved, err := resolve(ctx, unresolved)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return err
+	}
+	for i, id := range ids {
+		*id = resolved[i]
+	}
+	return nil
+}
+
+func nodeIDPointers(ids []string) []*string {
+	pointers := make([]*string, 0, len(ids))
+	for i := range ids {
+		pointers = append(pointers, &ids[i])
+	}
+	return pointers
+}
+
+// revertTargetTypes are the types of node edits, that record the state of the
+// node after a change to its texts.
+var revertTargetTypes = []db.NodeEditType{
+	db.NodeEditTypeCreate,
+	db.NodeEditTypeEdit,
+	db.NodeEditTypeRevert,
+	db.NodeEditTypeRestore,
+	db.NodeEditTypeRemoveTranslation,
+	db.NodeEditTypeMoveTranslation,
+}
+
+func isRevertTarget(editType db.NodeEditType) bool {
+	for _, t := range revertTargetTypes {
+		if t == editType {
+			return true
+		}
+	}
+	return false
+}
+
+// InvalidRevertTargetError is returned when a node should be reverted to an
+// edit, that is not of one of the revertTargetTypes.
+type InvalidRevertTargetError struct {
+	EditID string
+	Type   db.NodeEditType
+}
+
+func (e *InvalidRevertTargetError) Error() string {
+	return fmt.Sprintf("cannot revert to edit '%s' of type '%s', it records no state of the node", e.EditID, e.Type)
+}
+
+func (c *Controller) SubmitVote(ctx context.Context, id string, value float64) (*model.Status, error) {
+	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
+	if err != nil || !authenticated || user == nil {
+		if err != nil {
+			log.Ctx(ctx).Error().Msgf("%v", err)
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
+		return AuthNeededForGraphDataChangeStatus, ErrAuthNeededForGraphDataChange
+	}
+	if err := c.resolveEdgeIDs(ctx, &id); err != nil {
+		return nil, err
+	}
+	err = c.db.AddEdgeWeightVote(ctx, *user, id, value)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	// the weight changes once the vote is aggregated, see PeriodicVoteAggregation
+	log.Ctx(ctx).Debug().Msgf("SubmitVote() -> %v", nil)
+	return nil, nil
+}
+
+func (c *Controller) RetractVote(ctx context.Context, edgeID string) (*model.Status, error) {
+	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
+	if err != nil || !authenticated || user == nil {
+		if err != nil {
+			log.Ctx(ctx).Error().Msgf("%v", err)
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
+		return AuthNeededForGraphDataChangeStatus, ErrAuthNeededForGraphDataChange
+	}
+	if err := c.resolveEdgeIDs(ctx, &edgeID); err != nil {
+		return nil, err
+	}
+	err = c.db.RetractEdgeWeightVote(ctx, *user, edgeID)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	// the weight changes once the edge is aggregated, see PeriodicVoteAggregation
+	log.Ctx(ctx).Debug().Msgf("RetractVote() -> %v", nil)
+	return nil, nil
+}
+
+func (c *Controller) CreateTag(ctx context.Context, name model.Text) (*model.CreateEntityResult, error) {
+	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
+	if err != nil || !authenticated || user == nil {
+		if err != nil {
+			log.Ctx(ctx).Error().Msgf("%v", err)
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
+		return AuthNeededForGraphDataChangeResult, ErrAuthNeededForGraphDataChange
+	}
+	id, err := c.db.CreateTag(ctx, *user, &name)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	res := &model.CreateEntityResult{ID: id}
+	log.Ctx(ctx).Debug().Msgf("CreateTag() -> %v", res)
+	return res, nil
+}
+
+func (c *Controller) TagNode(ctx context.Context, nodeID, tagID string) (*model.Status, error) {
+	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
+	if err != nil || !authenticated || user == nil {
+		if err != nil {
+			log.Ctx(ctx).Error().Msgf("%v", err)
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
+		return AuthNeededForGraphDataChangeStatus, ErrAuthNeededForGraphDataChange
+	}
+	if err := c.resolveNodeIDs(ctx, &nodeID); err != nil {
+		return nil, err
+	}
+	err = c.db.TagNode(ctx, *user, nodeID, tagID)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	// tags do not change the layout of the graph
+	c.publishGraphChange(model.GraphChangeTypeNodeEdited, nodeID)
+	log.Ctx(ctx).Debug().Msgf("TagNode() -> %v", nil)
+	return nil, nil
+}
+
+func (c *Controller) UntagNode(ctx context.Context, nodeID, tagID string) (*model.Status, error) {
+	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
+	if err != nil || !authenticated || user == nil {
+		if err != nil {
+			log.Ctx(ctx).Error().Msgf("%v", err)
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
+		return AuthNeededForGraphDataChangeStatus, ErrAuthNeededForGraphDataChange
+	}
+	if err := c.resolveNodeIDs(ctx, &nodeID); err != nil {
+		return nil, err
+	}
+	err = c.db.UntagNode(ctx, *user, nodeID, tagID)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	// tags do not change the layout of the graph
+	c.publishGraphChange(model.GraphChangeTypeNodeEdited, nodeID)
+	log.Ctx(ctx).Debug().Msgf("UntagNode() -> %v", nil)
+	return nil, nil
+}
+
+func (c *Controller) Tags(ctx context.Context) ([]*model.Tag, error) {
+	tags, err := c.db.Tags(ctx)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	log.Ctx(ctx).Debug().Msgf("Tags() -> %v", tags)
+	return tags, nil
+}
+
+func (c *Controller) CreateResource(ctx context.Context, nodeID string, resource model.ResourceInput) (*model.CreateEntityResult, error) {
+	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
+	if err != nil || !authenticated || user == nil {
+		if err != nil {
+			log.Ctx(ctx).Error().Msgf("%v", err)
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
+		return AuthNeededForGraphDataChangeResult, ErrAuthNeededForGraphDataChange
+	}
+	if err := c.resolveNodeIDs(ctx, &nodeID); err != nil {
+		return nil, err
+	}
+	id, err := c.db.CreateResource(ctx, *user, nodeID, resource)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	res := &model.CreateEntityResult{ID: id}
+	log.Ctx(ctx).Debug().Msgf("CreateResource() -> %v", res)
+	return res, nil
+}
+
+func (c *Controller) EditResource(ctx context.Context, id string, resource model.ResourceInput) (*model.Status, error) {
+	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
+	if err != nil || !authenticated || user == nil {
+		if err != nil {
+			log.Ctx(ctx).Error().Msgf("%v", err)
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
+		return AuthNeededForGraphDataChangeStatus, ErrAuthNeededForGraphDataChange
+	}
+	err = c.db.EditResource(ctx, *user, id, resource)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	log.Ctx(ctx).Debug().Msgf("EditResource() -> %v", nil)
+	return nil, nil
+}
+
+func (c *Controller) DeleteResource(ctx context.Context, id string) (*model.Status, error) {
+	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
+	if err != nil || !authenticated || user == nil {
+		if err != nil {
+			log.Ctx(ctx).Error().Msgf("%v", err)
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
+		return AuthNeededForGraphDataChangeStatus, ErrAuthNeededForGraphDataChange
+	}
+	err = c.db.DeleteResource(ctx, *user, id)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	log.Ctx(ctx).Debug().Msgf("DeleteResource() -> %v", nil)
+	return nil, nil
+}
+
+func (c *Controller) VoteResource(ctx context.Context, id string) (*model.Status, error) {
+	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
+	if err != nil || !authenticated || user == nil {
+		if err != nil {
+			log.Ctx(ctx).Error().Msgf("%v", err)
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
+		return AuthNeededForGraphDataChangeStatus, ErrAuthNeededForGraphDataChange
+	}
+	err = c.db.VoteResource(ctx, *user, id)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	log.Ctx(ctx).Debug().Msgf("VoteResource() -> %v", nil)
+	return nil, nil
+}
+
+// NodeResources returns the resources of a node, optionally only those of
+// the given kind. The node is one just returned to the client, so its ID needs
+// no resolution.
+func (c *Controller) NodeResources(ctx context.Context, nodeID string, kind *model.ResourceKind) ([]*model.Resource, error) {
+	var (
+		resources []*model.Resource
+		err       error
+	)
+	if l := loadersFromCtx(ctx); l != nil {
+		resources, err = l.nodeResources.load(ctx, nodeID)
+	} else {
+		var byNode map[string][]*model.Resource
+		byNode, err = c.db.NodeResources(ctx, []string{nodeID})
+		resources = byNode[nodeID]
+	}
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	result := []*model.Resource{}
+	for _, resource := range resources {
+		if kind == nil || resource.Kind == *kind {
+			result = append(result, resource)
+		}
+	}
+	log.Ctx(ctx).Debug().Msgf("NodeResources() -> %v", result)
+	return result, nil
+}
+
+func (c *Controller) ResourceEdits(ctx context.Context, id string) ([]*model.ResourceEdit, error) {
+	edits, err := c.db.ResourceEdits(ctx, id)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	log.Ctx(ctx).Debug().Msgf("ResourceEdits() -> %v", edits)
+	return edits, nil
+}
+
+func (c *Controller) Graph(ctx context.Context, edgeTypes []model.EdgeType, tags []string) (*model.Graph, error) {
+	filter := db.GraphFilter{TagIDs: tags}
+	for _, edgeType := range edgeTypes {
+		filter.EdgeTypes = append(filter.EdgeTypes, db.EdgeType(edgeType))
+	}
+	g, err := c.db.Graph(ctx, filter)
+	if err != nil || g == nil {
+		log.Ctx(ctx).Error().Msgf("%v | graph=%v", err, g)
+	}
+	c.layouter.GetNodePositions(ctx, g)
+	log.Ctx(ctx).Debug().Msgf("Graph() returns %d nodes and %d edges", len(g.Nodes), len(g.Edges))
+	return g, err
+}
+
+// MyVote returns the most recent vote of the current user on the edge, nil
+// for requests without authentication.
+func (c *Controller) MyVote(ctx context.Context, edgeID string) (*float64, error) {
+	if l := loadersFromCtx(ctx); l != nil {
+		return l.myVotes.load(ctx, edgeID)
+	}
+	weights, err := c.myVotes(ctx, []string{edgeID})
+	return weights[edgeID], err
+}
+
+// myVotes returns the votes of the current user by edge ID, the given edges
+// are ignored, since all votes are loaded at once anyway.
+func (c *Controller) myVotes(ctx context.Context, _ []string) (map[string]*float64, error) {
+	weights := map[string]*float64{}
+	if middleware.CtxGetAuthentication(ctx) == "" {
+		return weights, nil
+	}
+	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
+	if err != nil || !authenticated || user == nil {
+		if err != nil {
+			log.Ctx(ctx).Error().Msgf("%v", err)
+		}
+		return weights, nil
+	}
+	votes, err := c.db.EdgeVotes(ctx, *user)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	for _, vote := range votes {
+		weight := vote.Weight
+		weights[vote.EdgeID] = &weight
+	}
+	return weights, nil
+}
+
+func (c *Controller) MyVotes(ctx context.Context) ([]*model.EdgeVote, error) {
+	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
+	if err != nil || !authenticated || user == nil {
+		if err != nil {
+			log.Ctx(ctx).Error().Msgf("%v", err)
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
+		return nil, errors.New("only logged in users have votes")
+	}
+	votes, err := c.db.EdgeVotes(ctx, *user)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	log.Ctx(ctx).Debug().Msgf("MyVotes() returns %d votes", len(votes))
+	return votes, nil
+}
+
+func (c *Controller) EdgeVoteStats(ctx context.Context, edgeID string) (*model.EdgeVoteStats, error) {
+	if err := c.resolveEdgeIDs(ctx, &edgeID); err != nil {
+		return nil, err
+	}
+	stats, err := c.db.EdgeVoteStats(ctx, edgeID)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	log.Ctx(ctx).Debug().Msgf("EdgeVoteStats() -> %v", stats)
+	return stats, nil
+}
+
+// prerequisitesOnly is the filter for algorithms on the graph of
+// prerequisites, i.e. those that need a dependency order of the nodes.
+var prerequisitesOnly = db.GraphFilter{EdgeTypes: []db.EdgeType{db.EdgeTypePrerequisite}}
+
+func (c *Controller) LearningPath(ctx context.Context, from, to string) (*model.Graph, error) {
+	if err := c.resolveNodeIDs(ctx, &from, &to); err != nil {
+		return nil, err
+	}
+	g, err := c.db.Graph(ctx, prerequisitesOnly)
+	if err != nil || g == nil {
+		log.Ctx(ctx).Error().Msgf("%v | graph=%v", err, g)
+		return nil, err
+	}
+	path, err := graphalgo.LearningPath(g, from, to)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	c.layouter.GetNodePositions(ctx, path)
+	log.Ctx(ctx).Debug().Msgf("LearningPath() returns %d nodes and %d edges", len(path.Nodes), len(path.Edges))
+	return path, nil
+}
+
+func (c *Controller) Prerequisites(ctx context.Context, nodeID string, maxDepth *int, minWeight *float64) (*model.Graph, error) {
+	if err := c.resolveNodeIDs(ctx, &nodeID); err != nil {
+		return nil, err
+	}
+	g, err := c.db.Graph(ctx, prerequisitesOnly)
+	if err != nil || g == nil {
+		log.Ctx(ctx).Error().Msgf("%v | graph=%v", err, g)
+		return nil, err
+	}
+	conf := graphalgo.PrerequisitesConfig{}
+	if maxDepth != nil {
+		conf.MaxDepth = *maxDepth
+	}
+	if minWeight != nil {
+		conf.MinWeight = *minWeight
+	}
+	prerequisites, err := graphalgo.Prerequisites(g, nodeID, conf)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	c.layouter.GetNodePositions(ctx, prerequisites)
+	log.Ctx(ctx).Debug().Msgf("Prerequisites() returns %d nodes and %d edges", len(prerequisites.Nodes), len(prerequisites.Edges))
+	return prerequisites, nil
+}
+
+func (c *Controller) Subgraph(ctx context.Context, center string, depth int, direction model.Direction) (*model.Graph, error) {
+	if err := c.resolveNodeIDs(ctx, &center); err != nil {
+		return nil, err
+	}
+	g, err := c.db.Subgraph(ctx, center, depth, direction)
+	if err != nil || g == nil {
+		log.Ctx(ctx).Error().Msgf("%v | graph=%v", err, g)
+		return nil, err
+	}
+	c.layouter.GetNodePositions(ctx, g)
+	log.Ctx(ctx).Debug().Msgf("Subgraph() returns %d nodes and %d edges", len(g.Nodes), len(g.Edges))
+	return g, nil
+}
+
+func (c *Controller) GraphInViewport(ctx context.Context, minX, minY, maxX, maxY float64, zoom *float64) (*model.Graph, error) {
+	if maxX < minX || maxY < minY {
+		err := errors.New("invalid viewport: max must not be smaller than min")
 		log.Ctx(ctx).Error().Msgf("%v", err)
 		return nil, err
 	}
-	res := &model.CreateEntityResult{ID: id}
-	c.graphChanged()
-	log.Ctx(ctx).Debug().Msgf("CreateNode() -> %v", res)
-	return res, nil
+	z := 1.0
+	if zoom != nil {
+		z = *zoom
+	}
+	viewport := layout.Rect{X: minX, Y: minY, Width: maxX - minX, Height: maxY - minY}
+	ids := c.layouter.NodesInViewport(ctx, viewport, z)
+	g, err := c.db.GraphOfNodes(ctx, ids)
+	if err != nil || g == nil {
+		log.Ctx(ctx).Error().Msgf("%v | graph=%v", err, g)
+		return nil, err
+	}
+	c.layouter.GetNodePositions(ctx, g)
+	log.Ctx(ctx).Debug().Msgf("GraphInViewport() returns %d nodes and %d edges", len(g.Nodes), len(g.Edges))
+	return g, nil
 }
 
-func (c *Controller) CreateEdge(ctx context.Context, from string, to string, weight float64) (*model.CreateEntityResult, error) {
+func (c *Controller) DeleteNode(ctx context.Context, id string) (*model.Status, error) {
 	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
 	if err != nil || !authenticated || user == nil {
 		if err != nil {
@@ -63,20 +692,22 @@ func (c *Controller) CreateEdge(ctx context.Context, from string, to string, wei
 			return nil, err
 		}
 		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
-		return AuthNeededForGraphDataChangeResult, ErrAuthNeededForGraphDataChange
+		return AuthNeededForGraphDataChangeStatus, ErrAuthNeededForGraphDataChange
 	}
-	ID, err := c.db.CreateEdge(ctx, *user, from, to, weight)
+	if err := c.resolveNodeIDs(ctx, &id); err != nil {
+		return nil, err
+	}
+	err = c.db.DeleteNode(ctx, *user, id)
 	if err != nil {
 		log.Ctx(ctx).Error().Msgf("%v", err)
 		return nil, err
 	}
-	res := &model.CreateEntityResult{ID: ID}
-	c.graphChanged()
-	log.Ctx(ctx).Debug().Msgf("CreateEdge() -> %v", res)
-	return res, nil
+	c.graphChanged(model.GraphChangeTypeNodeDeleted, id)
+	log.Ctx(ctx).Debug().Msgf("DeleteNode() -> %v", nil)
+	return nil, nil
 }
 
-func (c *Controller) EditNode(ctx context.Context, id string, description model.Text, resources *model.Text) (*model.Status, error) {
+func (c *Controller) DeleteEdge(ctx context.Context, id string) (*model.Status, error) {
 	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
 	if err != nil || !authenticated || user == nil {
 		if err != nil {
@@ -86,17 +717,20 @@ func (c *Controller) EditNode(ctx context.Context, id string, description model.
 		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
 		return AuthNeededForGraphDataChangeStatus, ErrAuthNeededForGraphDataChange
 	}
-	err = c.db.EditNode(ctx, *user, id, &description, resources)
+	if err := c.resolveEdgeIDs(ctx, &id); err != nil {
+		return nil, err
+	}
+	err = c.db.DeleteEdge(ctx, *user, id)
 	if err != nil {
 		log.Ctx(ctx).Error().Msgf("%v", err)
 		return nil, err
 	}
-	c.graphChanged()
-	log.Ctx(ctx).Debug().Msgf("EditNode() -> %v", nil)
+	c.graphChanged(model.GraphChangeTypeEdgeDeleted, id)
+	log.Ctx(ctx).Debug().Msgf("DeleteEdge() -> %v", nil)
 	return nil, nil
 }
 
-func (c *Controller) SubmitVote(ctx context.Context, id string, value float64) (*model.Status, error) {
+func (c *Controller) RestoreNode(ctx context.Context, id string) (*model.Status, error) {
 	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
 	if err != nil || !authenticated || user == nil {
 		if err != nil {
@@ -106,26 +740,78 @@ func (c *Controller) SubmitVote(ctx context.Context, id string, value float64) (
 		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
 		return AuthNeededForGraphDataChangeStatus, ErrAuthNeededForGraphDataChange
 	}
-	err = c.db.AddEdgeWeightVote(ctx, *user, id, value)
+	err = c.db.RestoreNode(ctx, *user, id)
 	if err != nil {
 		log.Ctx(ctx).Error().Msgf("%v", err)
 		return nil, err
 	}
-	log.Ctx(ctx).Debug().Msgf("SubmitVote() -> %v", nil)
+	c.graphChanged(model.GraphChangeTypeNodeCreated, id)
+	log.Ctx(ctx).Debug().Msgf("RestoreNode() -> %v", nil)
 	return nil, nil
 }
 
-func (c *Controller) Graph(ctx context.Context) (*model.Graph, error) {
-	g, err := c.db.Graph(ctx)
-	if err != nil || g == nil {
-		log.Ctx(ctx).Error().Msgf("%v | graph=%v", err, g)
+func (c *Controller) RestoreEdge(ctx context.Context, id string) (*model.Status, error) {
+	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
+	if err != nil || !authenticated || user == nil {
+		if err != nil {
+			log.Ctx(ctx).Error().Msgf("%v", err)
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
+		return AuthNeededForGraphDataChangeStatus, ErrAuthNeededForGraphDataChange
 	}
-	c.layouter.GetNodePositions(ctx, g)
-	log.Ctx(ctx).Debug().Msgf("Graph() returns %d nodes and %d edges", len(g.Nodes), len(g.Edges))
-	return g, err
+	if err := c.resolveEdgeIDs(ctx, &id); err != nil {
+		return nil, err
+	}
+	err = c.db.RestoreEdge(ctx, *user, id)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	c.graphChanged(model.GraphChangeTypeEdgeCreated, id)
+	log.Ctx(ctx).Debug().Msgf("RestoreEdge() -> %v", nil)
+	return nil, nil
 }
 
-func (c *Controller) DeleteNode(ctx context.Context, id string) (*model.Status, error) {
+func (c *Controller) DeletedNodes(ctx context.Context) ([]*model.DeletedNode, error) {
+	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
+	if err != nil || !authenticated || user == nil {
+		if err != nil {
+			log.Ctx(ctx).Error().Msgf("%v", err)
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
+		return nil, ErrAuthNeededForGraphDataChange
+	}
+	deleted, err := c.db.DeletedNodes(ctx, *user)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	log.Ctx(ctx).Debug().Msgf("DeletedNodes() -> %d entries", len(deleted))
+	return deleted, nil
+}
+
+func (c *Controller) DeletedEdges(ctx context.Context) ([]*model.DeletedEdge, error) {
+	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
+	if err != nil || !authenticated || user == nil {
+		if err != nil {
+			log.Ctx(ctx).Error().Msgf("%v", err)
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
+		return nil, ErrAuthNeededForGraphDataChange
+	}
+	deleted, err := c.db.DeletedEdges(ctx, *user)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	log.Ctx(ctx).Debug().Msgf("DeletedEdges() -> %d entries", len(deleted))
+	return deleted, nil
+}
+
+func (c *Controller) MergeNodes(ctx context.Context, keep, remove string) (*model.Status, error) {
 	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
 	if err != nil || !authenticated || user == nil {
 		if err != nil {
@@ -135,17 +821,21 @@ func (c *Controller) DeleteNode(ctx context.Context, id string) (*model.Status,
 		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
 		return AuthNeededForGraphDataChangeStatus, ErrAuthNeededForGraphDataChange
 	}
-	err = c.db.DeleteNode(ctx, *user, id)
+	if err := c.resolveNodeIDs(ctx, &keep, &remove); err != nil {
+		return nil, err
+	}
+	err = c.db.MergeNodes(ctx, *user, keep, remove)
 	if err != nil {
 		log.Ctx(ctx).Error().Msgf("%v", err)
 		return nil, err
 	}
-	c.graphChanged()
-	log.Ctx(ctx).Debug().Msgf("DeleteNode() -> %v", nil)
+	c.graphChanged(model.GraphChangeTypeNodeDeleted, remove)
+	c.publishGraphChange(model.GraphChangeTypeNodeEdited, keep)
+	log.Ctx(ctx).Debug().Msgf("MergeNodes() -> %v", nil)
 	return nil, nil
 }
 
-func (c *Controller) DeleteEdge(ctx context.Context, id string) (*model.Status, error) {
+func (c *Controller) RemoveTranslation(ctx context.Context, nodeID, language string, field model.TranslationField) (*model.Status, error) {
 	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
 	if err != nil || !authenticated || user == nil {
 		if err != nil {
@@ -155,17 +845,117 @@ func (c *Controller) DeleteEdge(ctx context.Context, id string) (*model.Status,
 		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
 		return AuthNeededForGraphDataChangeStatus, ErrAuthNeededForGraphDataChange
 	}
-	err = c.db.DeleteEdge(ctx, *user, id)
+	if err := c.resolveNodeIDs(ctx, &nodeID); err != nil {
+		return nil, err
+	}
+	err = c.db.RemoveTranslation(ctx, *user, nodeID, language, field)
 	if err != nil {
 		log.Ctx(ctx).Error().Msgf("%v", err)
 		return nil, err
 	}
-	c.graphChanged()
-	log.Ctx(ctx).Debug().Msgf("DeleteEdge() -> %v", nil)
+	// translations do not change the layout of the graph
+	c.publishGraphChange(model.GraphChangeTypeNodeEdited, nodeID)
+	log.Ctx(ctx).Debug().Msgf("RemoveTranslation() -> %v", nil)
+	return nil, nil
+}
+
+func (c *Controller) MoveTranslation(ctx context.Context, nodeID, fromLanguage, toLanguage string) (*model.Status, error) {
+	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
+	if err != nil || !authenticated || user == nil {
+		if err != nil {
+			log.Ctx(ctx).Error().Msgf("%v", err)
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
+		return AuthNeededForGraphDataChangeStatus, ErrAuthNeededForGraphDataChange
+	}
+	if err := c.resolveNodeIDs(ctx, &nodeID); err != nil {
+		return nil, err
+	}
+	err = c.db.MoveTranslation(ctx, *user, nodeID, fromLanguage, toLanguage)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	c.publishGraphChange(model.GraphChangeTypeNodeEdited, nodeID)
+	log.Ctx(ctx).Debug().Msgf("MoveTranslation() -> %v", nil)
 	return nil, nil
 }
 
+func (c *Controller) MoveTranslations(ctx context.Context, filter model.TranslationFilter, fromLanguage, toLanguage string) ([]string, error) {
+	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
+	if err != nil || !authenticated || user == nil {
+		if err != nil {
+			log.Ctx(ctx).Error().Msgf("%v", err)
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
+		return nil, ErrAuthNeededForGraphDataChange
+	}
+	if err := c.resolveNodeIDs(ctx, nodeIDPointers(filter.NodeIDs)...); err != nil {
+		return nil, err
+	}
+	changed, err := c.db.MoveTranslations(ctx, *user, toDBTranslationFilter(filter), fromLanguage, toLanguage)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	for _, id := range changed {
+		c.publishGraphChange(model.GraphChangeTypeNodeEdited, id)
+	}
+	log.Ctx(ctx).Debug().Msgf("MoveTranslations() -> %v", changed)
+	return changed, nil
+}
+
+func (c *Controller) RemoveTranslations(ctx context.Context, filter model.TranslationFilter, language string, field model.TranslationField) ([]string, error) {
+	authenticated, user, err := c.db.IsUserAuthenticated(ctx)
+	if err != nil || !authenticated || user == nil {
+		if err != nil {
+			log.Ctx(ctx).Error().Msgf("%v", err)
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Msgf("user '%s' (token '%s') not authenticated", middleware.CtxGetUserID(ctx), middleware.CtxGetAuthentication(ctx))
+		return nil, ErrAuthNeededForGraphDataChange
+	}
+	if err := c.resolveNodeIDs(ctx, nodeIDPointers(filter.NodeIDs)...); err != nil {
+		return nil, err
+	}
+	changed, err := c.db.RemoveTranslations(ctx, *user, toDBTranslationFilter(filter), language, field)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	for _, id := range changed {
+		c.publishGraphChange(model.GraphChangeTypeNodeEdited, id)
+	}
+	log.Ctx(ctx).Debug().Msgf("RemoveTranslations() -> %v", changed)
+	return changed, nil
+}
+
+func toDBTranslationFilter(filter model.TranslationFilter) db.TranslationFilter {
+	dbFilter := db.TranslationFilter{NodeIDs: filter.NodeIDs}
+	if filter.EditedBy != nil {
+		dbFilter.EditedBy = *filter.EditedBy
+	}
+	if filter.Matches != nil {
+		dbFilter.Matches = *filter.Matches
+	}
+	return dbFilter
+}
+
+// Node returns the node, a merged node is redirected to the node it was
+// merged into.
+func (c *Controller) Node(ctx context.Context, id string) (*model.Node, error) {
+	if err := c.resolveNodeIDs(ctx, &id); err != nil {
+		return nil, err
+	}
+	return c.db.Node(ctx, id)
+}
+
 func (c *Controller) NodeEdits(ctx context.Context, id string) ([]*model.NodeEdit, error) {
+	if err := c.resolveNodeIDs(ctx, &id); err != nil {
+		return nil, err
+	}
 	edits, err := c.db.NodeEdits(ctx, id)
 	if err != nil {
 		log.Ctx(ctx).Error().Msgf("%v", err)
@@ -176,6 +966,9 @@ func (c *Controller) NodeEdits(ctx context.Context, id string) ([]*model.NodeEdi
 }
 
 func (c *Controller) EdgeEdits(ctx context.Context, id string) ([]*model.EdgeEdit, error) {
+	if err := c.resolveEdgeIDs(ctx, &id); err != nil {
+		return nil, err
+	}
 	edits, err := c.db.EdgeEdits(ctx, id)
 	if err != nil {
 		log.Ctx(ctx).Error().Msgf("%v", err)
@@ -185,6 +978,59 @@ func (c *Controller) EdgeEdits(ctx context.Context, id string) ([]*model.EdgeEdi
 	return edits, nil
 }
 
+func (c *Controller) NodeEditDiff(ctx context.Context, nodeID, fromEdit, toEdit string) ([]*model.TranslationDiff, error) {
+	if err := c.resolveNodeIDs(ctx, &nodeID); err != nil {
+		return nil, err
+	}
+	edits, err := c.db.NodeEditsByID(ctx, nodeID, []string{fromEdit, toEdit})
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	from, to := edits[0].NewNode, edits[1].NewNode
+	languages := map[string]bool{}
+	for _, text := range []db.Text{from.Description, from.Resources, to.Description, to.Resources} {
+		for lang := range text {
+			languages[lang] = true
+		}
+	}
+	diffs := make([]*model.TranslationDiff, 0, len(languages))
+	for lang := range languages {
+		description, err := textdiff.Words(from.Description[lang], to.Description[lang])
+		if err != nil {
+			log.Ctx(ctx).Info().Msgf("%v", err)
+			return nil, err
+		}
+		resources, err := textdiff.Lines(from.Resources[lang], to.Resources[lang])
+		if err != nil {
+			log.Ctx(ctx).Info().Msgf("%v", err)
+			return nil, err
+		}
+		diffs = append(diffs, &model.TranslationDiff{
+			Language:    lang,
+			Description: diffChunksToModel(description),
+			Resources:   diffChunksToModel(resources),
+		})
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Language < diffs[j].Language })
+	log.Ctx(ctx).Debug().Msgf("NodeEditDiff() -> %d languages", len(diffs))
+	return diffs, nil
+}
+
+var diffOperationToModel = map[textdiff.Operation]model.DiffOperation{
+	textdiff.Equal:  model.DiffOperationEqual,
+	textdiff.Insert: model.DiffOperationInsert,
+	textdiff.Delete: model.DiffOperationDelete,
+}
+
+func diffChunksToModel(chunks []textdiff.Chunk) []*model.DiffChunk {
+	result := make([]*model.DiffChunk, 0, len(chunks))
+	for _, chunk := range chunks {
+		result = append(result, &model.DiffChunk{Operation: diffOperationToModel[chunk.Operation], Text: chunk.Text})
+	}
+	return result
+}
+
 // PeriodicGraphEmbeddingComputation periodically calls c.layouter.Reload() to
 // re-compute the graph embedding.
 func (c *Controller) PeriodicGraphEmbeddingComputation(ctx context.Context) {
@@ -199,16 +1045,94 @@ func (c *Controller) PeriodicGraphEmbeddingComputation(ctx context.Context) {
 	c.periodicGraphEmbeddingComputation(ctx, trigger, singleRunTimeout)
 }
 
-func (c *Controller) graphChanged() {
+// PeriodicTrashPurge permanently removes nodes and edges, that have been in
+// the trash for longer than retention. A retention <= 0 disables purging.
+func (c *Controller) PeriodicTrashPurge(ctx context.Context, retention time.Duration) {
+	if retention <= 0 {
+		return
+	}
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+	c.periodicTrashPurge(ctx, ticker.C, retention)
+}
+
+const trashPurgeInterval = time.Hour
+
+func (c *Controller) periodicTrashPurge(ctx context.Context, trigger <-chan time.Time, retention time.Duration) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-trigger:
+			if err := c.db.PurgeTrash(ctx, now.Add(-retention)); err != nil {
+				log.Ctx(ctx).Err(err).Msg("failed to purge trash")
+			}
+		}
+	}
+}
+
+// PeriodicVoteAggregation aggregates the votes submitted since its last run
+// into the weights of their edges.
+func (c *Controller) PeriodicVoteAggregation(ctx context.Context) {
+	ticker := time.NewTicker(voteAggregationInterval)
+	defer ticker.Stop()
+	c.periodicVoteAggregation(ctx, ticker.C)
+}
+
+const (
+	voteAggregationInterval  = 2 * time.Second
+	voteAggregationBatchSize = 100
+)
+
+func (c *Controller) periodicVoteAggregation(ctx context.Context, trigger <-chan time.Time) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-trigger:
+			c.aggregateVotes(ctx)
+		}
+	}
+}
+
+// aggregateVotes aggregates batches of edges until no edge with pending votes
+// is left, the layout is re-computed once per batch.
+func (c *Controller) aggregateVotes(ctx context.Context) {
+	for ctx.Err() == nil {
+		ids, err := c.db.AggregateDirtyEdges(ctx, voteAggregationBatchSize)
+		if err != nil {
+			log.Ctx(ctx).Err(err).Msg("failed to aggregate votes")
+			return
+		}
+		if len(ids) == 0 {
+			return
+		}
+		c.graphChanged(model.GraphChangeTypeEdgeVoted, ids...)
+		if len(ids) < voteAggregationBatchSize {
+			return
+		}
+	}
+}
+
+// graphChanged triggers a single re-computation of the graph embedding and
+// notifies subscribers about the change of every id.
+func (c *Controller) graphChanged(changeType model.GraphChangeType, ids ...string) {
 	select {
 	case c.graphChanges <- time.Now():
 	default:
 	}
+	for _, id := range ids {
+		c.publishGraphChange(changeType, id)
+	}
+}
+
+func (c *Controller) publishGraphChange(changeType model.GraphChangeType, id string) {
+	c.graphEvents.publish(&model.GraphChange{Type: changeType, ID: id, Time: time.Now()})
 }
 
 func (c *Controller) periodicGraphEmbeddingComputation(ctx context.Context, trigger <-chan time.Time, singleRunTimeout time.Duration) {
 	graph := func(ctx context.Context) *model.Graph {
-		g, err := c.db.Graph(ctx)
+		g, err := c.db.Graph(ctx, db.GraphFilter{})
 		if err != nil || g == nil {
 			log.Ctx(ctx).Err(err).Msg("failed to fetch graph from db for embedding computation")
 		}
@@ -225,6 +1149,7 @@ func (c *Controller) periodicGraphEmbeddingComputation(ctx context.Context, trig
 			stats.Iterations,
 			stats.TotalTime.Milliseconds(),
 		)
+		c.layoutEvents.publish(g)
 	}
 	{
 		// perform layouting once initially
@@ -248,8 +1173,79 @@ func (c *Controller) periodicGraphEmbeddingComputation(ctx context.Context, trig
 	}
 }
 
-func (c *Controller) NodeCompletion(ctx context.Context, substring string) ([]*model.Node, error) {
-	res, err := c.db.NodeMatchFuzzy(ctx, substring)
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+func (c *Controller) Search(ctx context.Context, query string, language *string, limit, offset *int) ([]*model.SearchHit, error) {
+	if strings.TrimSpace(query) == "" {
+		return []*model.SearchHit{}, nil
+	}
+	lang := middleware.CtxGetLanguage(ctx)
+	if language != nil {
+		lang = *language
+	}
+	if lang == "" {
+		lang = "en"
+	}
+	l, o := defaultSearchLimit, 0
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
+	}
+	if l < 0 || l > maxSearchLimit || o < 0 {
+		err := fmt.Errorf("invalid pagination: limit must be within [0,%d], offset must not be negative", maxSearchLimit)
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	res, err := c.db.Search(ctx, query, lang, l, o)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	log.Ctx(ctx).Debug().Msgf("Search() returns %d hits", len(res))
+	return res, nil
+}
+
+func (c *Controller) TranslationCoverage(ctx context.Context) ([]*model.TranslationCoverage, error) {
+	res, err := c.db.TranslationCoverage(ctx)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	log.Ctx(ctx).Debug().Msgf("TranslationCoverage() -> %v", res)
+	return res, nil
+}
+
+const defaultUntranslatedNodesPageSize = 20
+
+func (c *Controller) UntranslatedNodes(ctx context.Context, language string, first *int, after *string) ([]*model.Node, error) {
+	f, a := defaultUntranslatedNodesPageSize, ""
+	if first != nil {
+		f = *first
+	}
+	if after != nil {
+		a = *after
+	}
+	if f < 0 || f > maxSearchLimit {
+		err := fmt.Errorf("invalid pagination: first must be within [0,%d]", maxSearchLimit)
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	res, err := c.db.UntranslatedNodes(ctx, language, f, a)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("%v", err)
+		return nil, err
+	}
+	log.Ctx(ctx).Debug().Msgf("UntranslatedNodes() returns %d nodes", len(res))
+	return res, nil
+}
+
+func (c *Controller) NodeCompletion(ctx context.Context, substring string, tags []string) ([]*model.Node, error) {
+	res, err := c.db.NodeMatchFuzzy(ctx, substring, tags)
 	if err != nil {
 		log.Ctx(ctx).Err(err).Msgf("NodeCompletion(%v) -> %v", substring, res)
 	}
@@ -3,6 +3,9 @@ package controller
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -12,7 +15,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/suxatcode/learn-graph-poc-backend/db"
 	"github.com/suxatcode/learn-graph-poc-backend/graph/model"
+	"github.com/suxatcode/learn-graph-poc-backend/internal/controller/textdiff"
 	"github.com/suxatcode/learn-graph-poc-backend/layout"
+	"github.com/suxatcode/learn-graph-poc-backend/middleware"
 )
 
 var (
@@ -25,7 +30,9 @@ func TestController_CreateNode(t *testing.T) {
 		MockExpectations func(context.Context, db.MockDB)
 		ExpectRes        *model.CreateEntityResult
 		ExpectErr        bool
+		ExpectChanges    int
 		Description      model.Text
+		Force            bool
 	}{
 		{
 			Name: "user authenticated, node created",
@@ -33,12 +40,72 @@ func TestController_CreateNode(t *testing.T) {
 				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
 				mock.EXPECT().CreateNode(ctx, user444, &model.Text{Translations: []*model.Translation{
 					{Language: "en", Content: "ok"},
-				}}, nil).Return("123", nil)
+				}}, nil, false).Return("123", nil)
 			},
 			Description: model.Text{Translations: []*model.Translation{
 				{Language: "en", Content: "ok"},
 			}},
-			ExpectRes: &model.CreateEntityResult{ID: "123", Status: nil},
+			ExpectRes:     &model.CreateEntityResult{ID: "123", Status: nil},
+			ExpectChanges: 1,
+		},
+		{
+			Name: "mislabeled translation flagged with its language, not changed",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().CreateNode(ctx, user444, &model.Text{Translations: []*model.Translation{
+					{Language: "zh", Content: "The derivative of a function"},
+				}}, nil, false).Return("123", nil)
+			},
+			Description: model.Text{Translations: []*model.Translation{
+				{Language: "zh", Content: "The derivative of a function"},
+			}},
+			ExpectRes: &model.CreateEntityResult{ID: "123", Status: &model.Status{
+				Message: "'The derivative of a function' looks like 'en', not 'zh'",
+			}},
+			ExpectChanges: 1,
+		},
+		{
+			Name: "mislabeled translation flagged",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().CreateNode(ctx, user444, &model.Text{Translations: []*model.Translation{
+					{Language: "zh", Content: "Apple"},
+				}}, nil, false).Return("123", nil)
+			},
+			Description: model.Text{Translations: []*model.Translation{
+				{Language: "zh", Content: "Apple"},
+			}},
+			ExpectRes:     &model.CreateEntityResult{ID: "123", Status: &model.Status{Message: "'Apple' does not look like 'zh'"}},
+			ExpectChanges: 1,
+		},
+		{
+			Name: "similar node exists, no node created",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().CreateNode(ctx, user444, gomock.Any(), nil, false).Return("", &db.DuplicateNodesError{
+					Duplicates: []*model.Node{{ID: "7", Description: "ok!"}},
+				})
+			},
+			Description: model.Text{Translations: []*model.Translation{
+				{Language: "en", Content: "ok"},
+			}},
+			ExpectRes: &model.CreateEntityResult{Status: &model.Status{
+				Message:    "similar nodes already exist: 7",
+				Duplicates: []*model.Node{{ID: "7", Description: "ok!"}},
+			}},
+		},
+		{
+			Name: "similar node exists, node created by force",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().CreateNode(ctx, user444, gomock.Any(), nil, true).Return("123", nil)
+			},
+			Description: model.Text{Translations: []*model.Translation{
+				{Language: "en", Content: "ok"},
+			}},
+			Force:         true,
+			ExpectRes:     &model.CreateEntityResult{ID: "123", Status: nil},
+			ExpectChanges: 1,
 		},
 		{
 			Name: "user not authenticated, no node created",
@@ -56,16 +123,15 @@ func TestController_CreateNode(t *testing.T) {
 			ctx := context.Background()
 			test.MockExpectations(ctx, *db)
 			c := NewController(db, nil)
-			id, err := c.CreateNode(ctx, test.Description, nil)
+			id, err := c.CreateNode(ctx, test.Description, nil, test.Force)
 			assert := assert.New(t)
 			assert.Equal(test.ExpectRes, id)
 			if test.ExpectErr {
 				assert.Error(err)
-				assert.Equal(0, countChannel(c.graphChanges))
 			} else {
 				assert.NoError(err)
-				assert.Equal(1, countChannel(c.graphChanges))
 			}
+			assert.Equal(test.ExpectChanges, countChannel(c.graphChanges))
 		})
 	}
 }
@@ -81,10 +147,19 @@ func TestController_CreateEdge(t *testing.T) {
 			Name: "user authenticated, edge created",
 			MockExpectations: func(ctx context.Context, mock db.MockDB) {
 				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
-				mock.EXPECT().CreateEdge(ctx, user444, "1", "2", 42.42).Return("123", nil)
+				mock.EXPECT().CreateEdge(ctx, user444, "1", "2", 42.42, db.EdgeTypePrerequisite).Return("123", nil)
 			},
 			ExpectRes: &model.CreateEntityResult{ID: "123", Status: nil},
 		},
+		{
+			Name: "edge would create cycle",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().CreateEdge(ctx, user444, "1", "2", 42.42, db.EdgeTypePrerequisite).Return("", &db.CycleError{Cycle: []string{"1", "2", "1"}})
+			},
+			ExpectRes: &model.CreateEntityResult{ID: "", Status: &model.Status{Message: "edge would create a cycle: 1 -> 2 -> 1"}},
+			ExpectErr: true,
+		},
 		{
 			Name: "user not authenticated, no edge created",
 			MockExpectations: func(ctx context.Context, mock db.MockDB) {
@@ -98,10 +173,11 @@ func TestController_CreateEdge(t *testing.T) {
 			t.Log(test.Name)
 			ctrl := gomock.NewController(t)
 			db := db.NewMockDB(ctrl)
+			expectNoRedirects(db)
 			ctx := context.Background()
 			test.MockExpectations(ctx, *db)
 			c := NewController(db, nil)
-			id, err := c.CreateEdge(ctx, "1", "2", 42.42)
+			id, err := c.CreateEdge(ctx, "1", "2", 42.42, model.EdgeTypePrerequisite)
 			assert := assert.New(t)
 			assert.Equal(test.ExpectRes, id)
 			if test.ExpectErr {
@@ -137,6 +213,20 @@ func TestController_EditNode(t *testing.T) {
 			}},
 			NodeID: "123",
 		},
+		{
+			Name: "suspected language is only flagged, the translation is stored as submitted",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().EditNode(ctx, user444, "123", &model.Text{Translations: []*model.Translation{
+					{Language: "en", Content: "Schrödinger and Gödel"},
+				}}, nil).Return(nil)
+			},
+			Description: model.Text{Translations: []*model.Translation{
+				{Language: "en", Content: "Schrödinger and Gödel"},
+			}},
+			NodeID:    "123",
+			ExpectRes: &model.Status{Message: "'Schrödinger and Gödel' looks like 'de', not 'en'"},
+		},
 		{
 			Name: "user not authenticated, node not edited",
 			MockExpectations: func(ctx context.Context, mock db.MockDB) {
@@ -154,6 +244,7 @@ func TestController_EditNode(t *testing.T) {
 			t.Log(test.Name)
 			ctrl := gomock.NewController(t)
 			db := db.NewMockDB(ctrl)
+			expectNoRedirects(db)
 			ctx := context.Background()
 			test.MockExpectations(ctx, *db)
 			c := NewController(db, nil)
@@ -212,6 +303,7 @@ func TestController_EditNode_ShouldAlwaysLogOnError(t *testing.T) {
 			ctx := logger.WithContext(context.Background())
 			ctrl := gomock.NewController(t)
 			db := db.NewMockDB(ctrl)
+			expectNoRedirects(db)
 			test.MockExpectations(ctx, *db)
 			c := NewController(db, nil)
 			status, err := c.EditNode(ctx, "123", model.Text{Translations: []*model.Translation{{Language: "en", Content: "ok"}}}, nil)
@@ -229,6 +321,129 @@ func TestController_EditNode_ShouldAlwaysLogOnError(t *testing.T) {
 	}
 }
 
+func TestController_RevertNode(t *testing.T) {
+	editOfType := func(editType db.NodeEditType) []db.NodeEdit {
+		return []db.NodeEdit{{Document: db.Document{Key: "5"}, Node: "123", Type: editType}}
+	}
+	for _, test := range []struct {
+		Name             string
+		MockExpectations func(context.Context, db.MockDB)
+		ExpectRes        *model.Status
+		ExpectErr        bool
+		ExpectErrType    error
+	}{
+		{
+			Name: "user authenticated, node reverted",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().NodeEditsByID(ctx, "123", []string{"5"}).Return(editOfType(db.NodeEditTypeEdit), nil)
+				mock.EXPECT().RevertNode(ctx, user444, "123", "5").Return(nil)
+			},
+		},
+		{
+			Name: "user authenticated, node reverted to creation",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().NodeEditsByID(ctx, "123", []string{"5"}).Return(editOfType(db.NodeEditTypeCreate), nil)
+				mock.EXPECT().RevertNode(ctx, user444, "123", "5").Return(nil)
+			},
+		},
+		{
+			Name: "user authenticated, node reverted to a moved translation",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().NodeEditsByID(ctx, "123", []string{"5"}).Return(editOfType(db.NodeEditTypeMoveTranslation), nil)
+				mock.EXPECT().RevertNode(ctx, user444, "123", "5").Return(nil)
+			},
+		},
+		{
+			Name: "user authenticated, node reverted to a revert",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().NodeEditsByID(ctx, "123", []string{"5"}).Return(editOfType(db.NodeEditTypeRevert), nil)
+				mock.EXPECT().RevertNode(ctx, user444, "123", "5").Return(nil)
+			},
+		},
+		{
+			Name: "user not authenticated, node not reverted",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(false, nil, nil)
+			},
+			ExpectErr: true,
+			ExpectRes: AuthNeededForGraphDataChangeStatus,
+		},
+		{
+			Name: "edit does not exist",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().NodeEditsByID(ctx, "123", []string{"5"}).Return(nil, errors.New("does not exist"))
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "delete edit is no revert target",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().NodeEditsByID(ctx, "123", []string{"5"}).Return(editOfType(db.NodeEditTypeDelete), nil)
+			},
+			ExpectErr:     true,
+			ExpectErrType: &InvalidRevertTargetError{EditID: "5", Type: db.NodeEditTypeDelete},
+		},
+		{
+			Name: "tag edit is no revert target",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().NodeEditsByID(ctx, "123", []string{"5"}).Return(editOfType(db.NodeEditTypeTag), nil)
+			},
+			ExpectErr:     true,
+			ExpectErrType: &InvalidRevertTargetError{EditID: "5", Type: db.NodeEditTypeTag},
+		},
+		{
+			Name: "merge edit is no revert target",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().NodeEditsByID(ctx, "123", []string{"5"}).Return(editOfType(db.NodeEditTypeMerge), nil)
+			},
+			ExpectErr:     true,
+			ExpectErrType: &InvalidRevertTargetError{EditID: "5", Type: db.NodeEditTypeMerge},
+		},
+		{
+			Name: "db error",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().NodeEditsByID(ctx, "123", []string{"5"}).Return(editOfType(db.NodeEditTypeEdit), nil)
+				mock.EXPECT().RevertNode(ctx, user444, "123", "5").Return(errors.New("AAA"))
+			},
+			ExpectErr: true,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db := db.NewMockDB(ctrl)
+			expectNoRedirects(db)
+			ctx := context.Background()
+			test.MockExpectations(ctx, *db)
+			c := NewController(db, nil)
+			status, err := c.RevertNode(ctx, "123", "5")
+			assert := assert.New(t)
+			assert.Equal(test.ExpectRes, status)
+			if test.ExpectErr {
+				assert.Error(err)
+				assert.Equal(0, countChannel(c.graphChanges))
+			} else {
+				assert.NoError(err)
+				assert.Equal(1, countChannel(c.graphChanges))
+			}
+			if test.ExpectErrType != nil {
+				var revertErr *InvalidRevertTargetError
+				if assert.True(errors.As(err, &revertErr)) {
+					assert.Equal(test.ExpectErrType, revertErr)
+				}
+			}
+		})
+	}
+}
+
 func TestController_SubmitVote(t *testing.T) {
 	for _, test := range []struct {
 		Name             string
@@ -262,6 +477,7 @@ func TestController_SubmitVote(t *testing.T) {
 			t.Log(test.Name)
 			ctrl := gomock.NewController(t)
 			db := db.NewMockDB(ctrl)
+			expectNoRedirects(db)
 			ctx := context.Background()
 			test.MockExpectations(ctx, *db)
 			c := NewController(db, nil)
@@ -273,13 +489,13 @@ func TestController_SubmitVote(t *testing.T) {
 			} else {
 				assert.NoError(err)
 			}
-			// XXX(skep): should we re-run layout on votes? no..
-			// assert.Equal(1, countChannel(c.graphChanges))
+			// layout is re-run after aggregation, see aggregateVotes
+			assert.Equal(0, countChannel(c.graphChanges))
 		})
 	}
 }
 
-func TestController_DeleteNode(t *testing.T) {
+func TestController_RetractVote(t *testing.T) {
 	for _, test := range []struct {
 		Name             string
 		MockExpectations func(context.Context, db.MockDB)
@@ -287,33 +503,50 @@ func TestController_DeleteNode(t *testing.T) {
 		ExpectErr        bool
 	}{
 		{
-			Name: "user authenticated, node created",
+			Name: "user authenticated, vote retracted",
 			MockExpectations: func(ctx context.Context, mock db.MockDB) {
 				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
-				mock.EXPECT().DeleteNode(ctx, user444, "123").Return(nil)
+				mock.EXPECT().RetractEdgeWeightVote(ctx, user444, "123").Return(nil)
+			},
+		},
+		{
+			Name: "no vote to retract",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().RetractEdgeWeightVote(ctx, user444, "123").Return(errors.New("no vote"))
 			},
+			ExpectErr: true,
+		},
+		{
+			Name: "user not authenticated",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(false, nil, nil)
+			},
+			ExpectErr: true,
+			ExpectRes: AuthNeededForGraphDataChangeStatus,
 		},
 	} {
 		t.Run(test.Name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			db := db.NewMockDB(ctrl)
+			expectNoRedirects(db)
 			ctx := context.Background()
 			test.MockExpectations(ctx, *db)
 			c := NewController(db, nil)
-			status, err := c.DeleteNode(ctx, "123")
+			status, err := c.RetractVote(ctx, "123")
 			assert := assert.New(t)
 			assert.Equal(test.ExpectRes, status)
-			if !test.ExpectErr {
-				assert.NoError(err)
-			} else {
+			if test.ExpectErr {
 				assert.Error(err)
+			} else {
+				assert.NoError(err)
 			}
-			assert.Equal(1, countChannel(c.graphChanges))
+			assert.Equal(0, countChannel(c.graphChanges))
 		})
 	}
 }
 
-func TestController_DeleteEdge(t *testing.T) {
+func TestController_DeleteNode(t *testing.T) {
 	for _, test := range []struct {
 		Name             string
 		MockExpectations func(context.Context, db.MockDB)
@@ -324,17 +557,18 @@ func TestController_DeleteEdge(t *testing.T) {
 			Name: "user authenticated, node created",
 			MockExpectations: func(ctx context.Context, mock db.MockDB) {
 				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
-				mock.EXPECT().DeleteEdge(ctx, user444, "123").Return(nil)
+				mock.EXPECT().DeleteNode(ctx, user444, "123").Return(nil)
 			},
 		},
 	} {
 		t.Run(test.Name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			db := db.NewMockDB(ctrl)
+			expectNoRedirects(db)
 			ctx := context.Background()
 			test.MockExpectations(ctx, *db)
 			c := NewController(db, nil)
-			status, err := c.DeleteEdge(ctx, "123")
+			status, err := c.DeleteNode(ctx, "123")
 			assert := assert.New(t)
 			assert.Equal(test.ExpectRes, status)
 			if !test.ExpectErr {
@@ -347,62 +581,70 @@ func TestController_DeleteEdge(t *testing.T) {
 	}
 }
 
-func TestController_NodeEdits(t *testing.T) {
+func TestController_DeleteEdge(t *testing.T) {
 	for _, test := range []struct {
 		Name             string
 		MockExpectations func(context.Context, db.MockDB)
-		ExpectRes        []*model.NodeEdit
+		ExpectRes        *model.Status
 		ExpectErr        bool
 	}{
 		{
-			Name: "single Node Edit",
+			Name: "user authenticated, node created",
 			MockExpectations: func(ctx context.Context, mock db.MockDB) {
-				mock.EXPECT().NodeEdits(ctx, "123").Return([]*model.NodeEdit{{
-					Username: "Me Me",
-				}},
-					nil)
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().DeleteEdge(ctx, user444, "123").Return(nil)
 			},
-			ExpectRes: []*model.NodeEdit{{
-				Username: "Me Me",
-			}},
 		},
 	} {
 		t.Run(test.Name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			db := db.NewMockDB(ctrl)
+			expectNoRedirects(db)
 			ctx := context.Background()
 			test.MockExpectations(ctx, *db)
 			c := NewController(db, nil)
-			edits, err := c.NodeEdits(ctx, "123")
+			status, err := c.DeleteEdge(ctx, "123")
 			assert := assert.New(t)
-			assert.Equal(test.ExpectRes, edits)
+			assert.Equal(test.ExpectRes, status)
 			if !test.ExpectErr {
 				assert.NoError(err)
 			} else {
 				assert.Error(err)
 			}
+			assert.Equal(1, countChannel(c.graphChanges))
 		})
 	}
 }
 
-func TestController_EdgeEdits(t *testing.T) {
+func TestController_RestoreNode(t *testing.T) {
 	for _, test := range []struct {
 		Name             string
 		MockExpectations func(context.Context, db.MockDB)
-		ExpectRes        []*model.EdgeEdit
+		ExpectRes        *model.Status
 		ExpectErr        bool
 	}{
 		{
-			Name: "single Edge Edit",
+			Name: "user authenticated, node restored",
 			MockExpectations: func(ctx context.Context, mock db.MockDB) {
-				mock.EXPECT().EdgeEdits(ctx, "123").Return([]*model.EdgeEdit{{
-					Username: "Me Me",
-				}},
-					nil)
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().RestoreNode(ctx, user444, "123").Return(nil)
 			},
-			ExpectRes: []*model.EdgeEdit{{
-				Username: "Me Me",
-			}},
+		},
+		{
+			Name: "user not authenticated, node not restored",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(false, nil, nil)
+			},
+			ExpectErr: true,
+			ExpectRes: AuthNeededForGraphDataChangeStatus,
+		},
+		{
+			Name: "db error, e.g. user is no admin",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().RestoreNode(ctx, user444, "123").Return(errors.New("AAA"))
+			},
+			ExpectErr: true,
 		},
 	} {
 		t.Run(test.Name, func(t *testing.T) {
@@ -411,159 +653,1276 @@ func TestController_EdgeEdits(t *testing.T) {
 			ctx := context.Background()
 			test.MockExpectations(ctx, *db)
 			c := NewController(db, nil)
-			edits, err := c.EdgeEdits(ctx, "123")
+			status, err := c.RestoreNode(ctx, "123")
 			assert := assert.New(t)
-			assert.Equal(test.ExpectRes, edits)
-			if !test.ExpectErr {
-				assert.NoError(err)
-			} else {
+			assert.Equal(test.ExpectRes, status)
+			if test.ExpectErr {
 				assert.Error(err)
+				assert.Equal(0, countChannel(c.graphChanges))
+			} else {
+				assert.NoError(err)
+				assert.Equal(1, countChannel(c.graphChanges))
 			}
 		})
 	}
 }
 
-func TestController_Graph(t *testing.T) {
+func TestController_DeletedEdges(t *testing.T) {
 	for _, test := range []struct {
 		Name             string
-		MockExpectations func(context.Context, db.MockDB, MockLayouter)
-		ExpectGraph      *model.Graph
-		ExpectRes        *model.Status
+		MockExpectations func(context.Context, db.MockDB)
+		ExpectRes        []*model.DeletedEdge
 		ExpectErr        bool
 	}{
 		{
-			Name:        "assume added positions",
-			ExpectGraph: &model.Graph{Nodes: []*model.Node{{Position: &model.Vector{X: 1, Y: 2, Z: 3}}}},
-			MockExpectations: func(ctx context.Context, mockDB db.MockDB, mockLayouter MockLayouter) {
-				mockDB.EXPECT().Graph(ctx).Return(&model.Graph{
-					Nodes: []*model.Node{{}},
-				}, nil)
-				mockLayouter.EXPECT().GetNodePositions(ctx, gomock.Eq(&model.Graph{
-					Nodes: []*model.Node{{}},
-				})).DoAndReturn(
-					func(ctx context.Context, g *model.Graph) {
-						g.Nodes[0].Position = &model.Vector{X: 1, Y: 2, Z: 3}
-					},
-				)
+			Name: "user authenticated",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().DeletedEdges(ctx, user444).Return([]*model.DeletedEdge{{Edge: &model.Edge{ID: "1"}}}, nil)
+			},
+			ExpectRes: []*model.DeletedEdge{{Edge: &model.Edge{ID: "1"}}},
+		},
+		{
+			Name: "user not authenticated",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(false, nil, nil)
 			},
+			ExpectErr: true,
 		},
 	} {
 		t.Run(test.Name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			db := db.NewMockDB(ctrl)
-			l := NewMockLayouter(ctrl)
 			ctx := context.Background()
-			test.MockExpectations(ctx, *db, *l)
-			c := NewController(db, l)
-			graph, err := c.Graph(ctx)
+			test.MockExpectations(ctx, *db)
+			c := NewController(db, nil)
+			deleted, err := c.DeletedEdges(ctx)
 			assert := assert.New(t)
+			assert.Equal(test.ExpectRes, deleted)
 			if test.ExpectErr {
 				assert.Error(err)
 			} else {
-				if !assert.NoError(err) {
-					return
-				}
+				assert.NoError(err)
+			}
+		})
+	}
+}
+
+func TestController_TagNode(t *testing.T) {
+	for _, test := range []struct {
+		Name             string
+		MockExpectations func(context.Context, db.MockDB)
+		ExpectRes        *model.Status
+		ExpectErr        bool
+	}{
+		{
+			Name: "user authenticated, node tagged",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().TagNode(ctx, user444, "123", "7").Return(nil)
+			},
+		},
+		{
+			Name: "user not authenticated, node not tagged",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(false, nil, nil)
+			},
+			ExpectErr: true,
+			ExpectRes: AuthNeededForGraphDataChangeStatus,
+		},
+		{
+			Name: "db error",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().TagNode(ctx, user444, "123", "7").Return(errors.New("AAA"))
+			},
+			ExpectErr: true,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db := db.NewMockDB(ctrl)
+			expectNoRedirects(db)
+			ctx := context.Background()
+			test.MockExpectations(ctx, *db)
+			c := NewController(db, nil)
+			status, err := c.TagNode(ctx, "123", "7")
+			assert := assert.New(t)
+			assert.Equal(test.ExpectRes, status)
+			if test.ExpectErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			assert.Equal(0, countChannel(c.graphChanges), "tags must not trigger a layout run")
+		})
+	}
+}
+
+func TestController_CreateTag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := db.NewMockDB(ctrl)
+	ctx := context.Background()
+	name := model.Text{Translations: []*model.Translation{{Language: "en", Content: "math"}}}
+	mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+	mock.EXPECT().CreateTag(ctx, user444, &name).Return("7", nil)
+	c := NewController(mock, nil)
+	res, err := c.CreateTag(ctx, name)
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.Equal(&model.CreateEntityResult{ID: "7"}, res)
+}
+
+func TestController_CreateResource(t *testing.T) {
+	resource := model.ResourceInput{
+		URL:      "https://example.com/video",
+		Title:    &model.Text{Translations: []*model.Translation{{Language: "en", Content: "A video"}}},
+		Kind:     model.ResourceKindVideo,
+		Language: "en",
+	}
+	for _, test := range []struct {
+		Name             string
+		MockExpectations func(context.Context, db.MockDB)
+		ExpectRes        *model.CreateEntityResult
+		ExpectErr        bool
+	}{
+		{
+			Name: "user authenticated, resource created",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().CreateResource(ctx, user444, "123", resource).Return("9", nil)
+			},
+			ExpectRes: &model.CreateEntityResult{ID: "9"},
+		},
+		{
+			Name: "user not authenticated",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(false, nil, nil)
+			},
+			ExpectRes: AuthNeededForGraphDataChangeResult,
+			ExpectErr: true,
+		},
+		{
+			Name: "db error",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().CreateResource(ctx, user444, "123", resource).Return("", errors.New("AAA"))
+			},
+			ExpectErr: true,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db := db.NewMockDB(ctrl)
+			expectNoRedirects(db)
+			ctx := context.Background()
+			test.MockExpectations(ctx, *db)
+			c := NewController(db, nil)
+			res, err := c.CreateResource(ctx, "123", resource)
+			assert := assert.New(t)
+			assert.Equal(test.ExpectRes, res)
+			if test.ExpectErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
+
+func TestController_VoteResource(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := db.NewMockDB(ctrl)
+	ctx := context.Background()
+	mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+	mock.EXPECT().VoteResource(ctx, user444, "9").Return(nil)
+	c := NewController(mock, nil)
+	status, err := c.VoteResource(ctx, "9")
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.Nil(status)
+}
+
+func TestController_redirectedIDs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := db.NewMockDB(ctrl)
+	ctx := context.Background()
+	description := model.Text{Translations: []*model.Translation{{Language: "en", Content: "Seed"}}}
+	mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil).Times(3)
+	gomock.InOrder(
+		mock.EXPECT().ResolveNodeIDs(ctx, []string{"2"}).Return([]string{"1"}, nil),
+		mock.EXPECT().EditNode(ctx, user444, "1", &description, nil).Return(nil),
+	)
+	gomock.InOrder(
+		mock.EXPECT().ResolveNodeIDs(ctx, []string{"2", "3"}).Return([]string{"1", "3"}, nil),
+		mock.EXPECT().CreateEdge(ctx, user444, "1", "3", 5.0, db.EdgeTypePrerequisite).Return("7", nil),
+	)
+	gomock.InOrder(
+		mock.EXPECT().ResolveEdgeIDs(ctx, []string{"8"}).Return([]string{"7"}, nil),
+		mock.EXPECT().AddEdgeWeightVote(ctx, user444, "7", 4.0).Return(nil),
+	)
+	c := NewController(mock, nil)
+	assert := assert.New(t)
+	_, err := c.EditNode(ctx, "2", description, nil)
+	assert.NoError(err)
+	res, err := c.CreateEdge(ctx, "2", "3", 5, model.EdgeTypePrerequisite)
+	assert.NoError(err)
+	assert.Equal("7", res.ID)
+	_, err = c.SubmitVote(ctx, "8", 4)
+	assert.NoError(err)
+	gomock.InOrder(
+		mock.EXPECT().ResolveNodeIDs(ctx, []string{"2"}).Return([]string{"1"}, nil),
+		mock.EXPECT().Node(ctx, "1").Return(&model.Node{ID: "1"}, nil),
+	)
+	node, err := c.Node(ctx, "2")
+	assert.NoError(err)
+	assert.Equal(&model.Node{ID: "1"}, node)
+
+	mock.EXPECT().ResolveNodeIDs(ctx, []string{"2"}).Return(nil, errors.New("db down"))
+	g, err := c.Subgraph(ctx, "2", 1, model.DirectionBoth)
+	assert.Error(err)
+	assert.Nil(g)
+}
+
+func TestController_MergeNodes(t *testing.T) {
+	for _, test := range []struct {
+		Name             string
+		MockExpectations func(context.Context, db.MockDB)
+		ExpectRes        *model.Status
+		ExpectErr        bool
+	}{
+		{
+			Name: "user authenticated, nodes merged",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().MergeNodes(ctx, user444, "1", "2").Return(nil)
+			},
+		},
+		{
+			Name: "user not authenticated",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(false, nil, nil)
+			},
+			ExpectErr: true,
+			ExpectRes: AuthNeededForGraphDataChangeStatus,
+		},
+		{
+			Name: "db error, e.g. user is no moderator",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().MergeNodes(ctx, user444, "1", "2").Return(errors.New("AAA"))
+			},
+			ExpectErr: true,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db := db.NewMockDB(ctrl)
+			expectNoRedirects(db)
+			ctx := context.Background()
+			test.MockExpectations(ctx, *db)
+			c := NewController(db, nil)
+			status, err := c.MergeNodes(ctx, "1", "2")
+			assert := assert.New(t)
+			assert.Equal(test.ExpectRes, status)
+			if test.ExpectErr {
+				assert.Error(err)
+				assert.Equal(0, countChannel(c.graphChanges))
+			} else {
+				assert.NoError(err)
+				assert.Equal(1, countChannel(c.graphChanges))
+			}
+		})
+	}
+}
+
+func TestController_MoveTranslations(t *testing.T) {
+	editedBy, matches := "8", "^[a-z]"
+	for _, test := range []struct {
+		Name             string
+		MockExpectations func(context.Context, db.MockDB)
+		ExpectRes        []string
+		ExpectErr        bool
+	}{
+		{
+			Name: "user authenticated, translations moved",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().MoveTranslations(ctx, user444, db.TranslationFilter{NodeIDs: []string{"1", "2"}, EditedBy: "8", Matches: "^[a-z]"}, "zh", "en").Return([]string{"2"}, nil)
+			},
+			ExpectRes: []string{"2"},
+		},
+		{
+			Name: "user not authenticated",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(false, nil, nil)
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "db error, e.g. user is no admin",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().MoveTranslations(ctx, user444, gomock.Any(), "zh", "en").Return(nil, errors.New("AAA"))
+			},
+			ExpectErr: true,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db := db.NewMockDB(ctrl)
+			expectNoRedirects(db)
+			ctx := context.Background()
+			test.MockExpectations(ctx, *db)
+			c := NewController(db, nil)
+			filter := model.TranslationFilter{NodeIDs: []string{"1", "2"}, EditedBy: &editedBy, Matches: &matches}
+			res, err := c.MoveTranslations(ctx, filter, "zh", "en")
+			assert := assert.New(t)
+			if test.ExpectErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			assert.Equal(test.ExpectRes, res)
+		})
+	}
+}
+
+func TestController_RemoveTranslations(t *testing.T) {
+	editedBy := "8"
+	for _, test := range []struct {
+		Name             string
+		MockExpectations func(context.Context, db.MockDB)
+		ExpectRes        []string
+		ExpectErr        bool
+	}{
+		{
+			Name: "user authenticated, translations removed",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().RemoveTranslations(ctx, user444, db.TranslationFilter{NodeIDs: []string{"1", "2"}, EditedBy: "8"}, "zh", model.TranslationFieldResources).Return([]string{"1", "2"}, nil)
+			},
+			ExpectRes: []string{"1", "2"},
+		},
+		{
+			Name: "user not authenticated",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(false, nil, nil)
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "db error, e.g. user is no admin",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().RemoveTranslations(ctx, user444, gomock.Any(), "zh", model.TranslationFieldResources).Return(nil, errors.New("AAA"))
+			},
+			ExpectErr: true,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db := db.NewMockDB(ctrl)
+			expectNoRedirects(db)
+			ctx := context.Background()
+			test.MockExpectations(ctx, *db)
+			c := NewController(db, nil)
+			filter := model.TranslationFilter{NodeIDs: []string{"1", "2"}, EditedBy: &editedBy}
+			res, err := c.RemoveTranslations(ctx, filter, "zh", model.TranslationFieldResources)
+			assert := assert.New(t)
+			if test.ExpectErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			assert.Equal(test.ExpectRes, res)
+		})
+	}
+}
+
+func TestController_NodeEditDiff(t *testing.T) {
+	for _, test := range []struct {
+		Name             string
+		MockExpectations func(context.Context, db.MockDB)
+		ExpectRes        []*model.TranslationDiff
+		ExpectErr        bool
+	}{
+		{
+			Name: "diff per language",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().NodeEditsByID(ctx, "123", []string{"1", "2"}).Return([]db.NodeEdit{
+					{NewNode: db.Node{Description: db.Text{"en": "a b", "de": "x"}, Resources: db.Text{"en": "r\n"}}},
+					{NewNode: db.Node{Description: db.Text{"en": "a c"}, Resources: db.Text{"en": "r\ns\n"}}},
+				}, nil)
+			},
+			ExpectRes: []*model.TranslationDiff{
+				{
+					Language:    "de",
+					Description: []*model.DiffChunk{{Operation: model.DiffOperationDelete, Text: "x"}},
+					Resources:   []*model.DiffChunk{},
+				},
+				{
+					Language: "en",
+					Description: []*model.DiffChunk{
+						{Operation: model.DiffOperationEqual, Text: "a "},
+						{Operation: model.DiffOperationDelete, Text: "b"},
+						{Operation: model.DiffOperationInsert, Text: "c"},
+					},
+					Resources: []*model.DiffChunk{
+						{Operation: model.DiffOperationEqual, Text: "r\n"},
+						{Operation: model.DiffOperationInsert, Text: "s\n"},
+					},
+				},
+			},
+		},
+		{
+			Name: "db error",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().NodeEditsByID(ctx, "123", []string{"1", "2"}).Return(nil, errors.New("AAA"))
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "text too large",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().NodeEditsByID(ctx, "123", []string{"1", "2"}).Return([]db.NodeEdit{
+					{NewNode: db.Node{Description: db.Text{"en": strings.Repeat("a ", textdiff.MaxTokens)}}},
+					{NewNode: db.Node{Description: db.Text{"en": "a"}}},
+				}, nil)
+			},
+			ExpectErr: true,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db := db.NewMockDB(ctrl)
+			expectNoRedirects(db)
+			ctx := context.Background()
+			test.MockExpectations(ctx, *db)
+			c := NewController(db, nil)
+			diffs, err := c.NodeEditDiff(ctx, "123", "1", "2")
+			assert := assert.New(t)
+			if test.ExpectErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(test.ExpectRes, diffs)
+		})
+	}
+}
+
+func TestController_NodeEdits(t *testing.T) {
+	for _, test := range []struct {
+		Name             string
+		MockExpectations func(context.Context, db.MockDB)
+		ExpectRes        []*model.NodeEdit
+		ExpectErr        bool
+	}{
+		{
+			Name: "single Node Edit",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().NodeEdits(ctx, "123").Return([]*model.NodeEdit{{
+					Username: "Me Me",
+				}},
+					nil)
+			},
+			ExpectRes: []*model.NodeEdit{{
+				Username: "Me Me",
+			}},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db := db.NewMockDB(ctrl)
+			expectNoRedirects(db)
+			ctx := context.Background()
+			test.MockExpectations(ctx, *db)
+			c := NewController(db, nil)
+			edits, err := c.NodeEdits(ctx, "123")
+			assert := assert.New(t)
+			assert.Equal(test.ExpectRes, edits)
+			if !test.ExpectErr {
+				assert.NoError(err)
+			} else {
+				assert.Error(err)
+			}
+		})
+	}
+}
+
+func TestController_EdgeEdits(t *testing.T) {
+	for _, test := range []struct {
+		Name             string
+		MockExpectations func(context.Context, db.MockDB)
+		ExpectRes        []*model.EdgeEdit
+		ExpectErr        bool
+	}{
+		{
+			Name: "single Edge Edit",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().EdgeEdits(ctx, "123").Return([]*model.EdgeEdit{{
+					Username: "Me Me",
+				}},
+					nil)
+			},
+			ExpectRes: []*model.EdgeEdit{{
+				Username: "Me Me",
+			}},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db := db.NewMockDB(ctrl)
+			expectNoRedirects(db)
+			ctx := context.Background()
+			test.MockExpectations(ctx, *db)
+			c := NewController(db, nil)
+			edits, err := c.EdgeEdits(ctx, "123")
+			assert := assert.New(t)
+			assert.Equal(test.ExpectRes, edits)
+			if !test.ExpectErr {
+				assert.NoError(err)
+			} else {
+				assert.Error(err)
+			}
+		})
+	}
+}
+
+func TestController_Graph(t *testing.T) {
+	for _, test := range []struct {
+		Name             string
+		MockExpectations func(context.Context, db.MockDB, MockLayouter)
+		EdgeTypes        []model.EdgeType
+		Tags             []string
+		ExpectGraph      *model.Graph
+		ExpectRes        *model.Status
+		ExpectErr        bool
+	}{
+		{
+			Name:        "filter by edge types",
+			EdgeTypes:   []model.EdgeType{model.EdgeTypeRelated, model.EdgeTypePartOf},
+			ExpectGraph: &model.Graph{},
+			MockExpectations: func(ctx context.Context, mockDB db.MockDB, mockLayouter MockLayouter) {
+				mockDB.EXPECT().Graph(ctx, db.GraphFilter{EdgeTypes: []db.EdgeType{db.EdgeTypeRelated, db.EdgeTypePartOf}}).Return(&model.Graph{}, nil)
+				mockLayouter.EXPECT().GetNodePositions(ctx, &model.Graph{})
+			},
+		},
+		{
+			Name:        "filter by tags",
+			Tags:        []string{"7"},
+			ExpectGraph: &model.Graph{},
+			MockExpectations: func(ctx context.Context, mockDB db.MockDB, mockLayouter MockLayouter) {
+				mockDB.EXPECT().Graph(ctx, db.GraphFilter{TagIDs: []string{"7"}}).Return(&model.Graph{}, nil)
+				mockLayouter.EXPECT().GetNodePositions(ctx, &model.Graph{})
+			},
+		},
+		{
+			Name:        "assume added positions",
+			ExpectGraph: &model.Graph{Nodes: []*model.Node{{Position: &model.Vector{X: 1, Y: 2, Z: 3}}}},
+			MockExpectations: func(ctx context.Context, mockDB db.MockDB, mockLayouter MockLayouter) {
+				mockDB.EXPECT().Graph(ctx, db.GraphFilter{}).Return(&model.Graph{
+					Nodes: []*model.Node{{}},
+				}, nil)
+				mockLayouter.EXPECT().GetNodePositions(ctx, gomock.Eq(&model.Graph{
+					Nodes: []*model.Node{{}},
+				})).DoAndReturn(
+					func(ctx context.Context, g *model.Graph) {
+						g.Nodes[0].Position = &model.Vector{X: 1, Y: 2, Z: 3}
+					},
+				)
+			},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db := db.NewMockDB(ctrl)
+			l := NewMockLayouter(ctrl)
+			ctx := context.Background()
+			test.MockExpectations(ctx, *db, *l)
+			c := NewController(db, l)
+			graph, err := c.Graph(ctx, test.EdgeTypes, test.Tags)
+			assert := assert.New(t)
+			if test.ExpectErr {
+				assert.Error(err)
+			} else {
+				if !assert.NoError(err) {
+					return
+				}
 			}
 			assert.Equal(test.ExpectGraph, graph)
 		})
 	}
 }
 
-func TestController_periodicGraphEmbeddingComputation(t *testing.T) {
+func TestController_periodicGraphEmbeddingComputation(t *testing.T) {
+	for _, test := range []struct {
+		Name             string
+		MockExpectations func(context.Context, db.MockDB, MockLayouter)
+		Setup            func(trigger chan time.Time)
+	}{
+		{
+			Name: "should run layout on startup",
+			MockExpectations: func(ctx context.Context, mockDB db.MockDB, mockLayouter MockLayouter) {
+				mockDB.EXPECT().Graph(gomock.Any(), db.GraphFilter{}).Return(&model.Graph{Nodes: []*model.Node{{}, {}}}, nil)
+				mockLayouter.EXPECT().Reload(gomock.Any(), &model.Graph{Nodes: []*model.Node{{}, {}}}).Return(layout.Stats{Iterations: 5})
+			},
+		},
+		{
+			Name: "should run layout on trigger call",
+			MockExpectations: func(ctx context.Context, mockDB db.MockDB, mockLayouter MockLayouter) {
+				mockDB.EXPECT().Graph(gomock.Any(), db.GraphFilter{}).Return(&model.Graph{Nodes: []*model.Node{{}, {}}}, nil)
+				mockLayouter.EXPECT().Reload(gomock.Any(), &model.Graph{Nodes: []*model.Node{{}, {}}}).Return(layout.Stats{Iterations: 5})
+				// 2nd call
+				mockDB.EXPECT().Graph(gomock.Any(), db.GraphFilter{}).Return(&model.Graph{Nodes: []*model.Node{{}, {}}}, nil)
+				mockLayouter.EXPECT().Reload(gomock.Any(), &model.Graph{Nodes: []*model.Node{{}, {}}}).Return(layout.Stats{Iterations: 5})
+			},
+			Setup: func(trigger chan time.Time) {
+				trigger <- time.UnixMilli(7)
+			},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db := db.NewMockDB(ctrl)
+			l := NewMockLayouter(ctrl)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			test.MockExpectations(ctx, *db, *l)
+			c := NewController(db, l)
+			trigger := make(chan time.Time, 10)
+			if test.Setup != nil {
+				test.Setup(trigger)
+			}
+			go c.periodicGraphEmbeddingComputation(ctx, trigger, time.Second*1)
+			time.Sleep(time.Millisecond * 10) // XXX(skep): could be a flaky test some day: can we do it without sleeping?
+		})
+	}
+}
+
+// expectNoRedirects resolves all node and edge IDs to themselves, i.e. as if
+// no nodes were merged.
+func expectNoRedirects(mock *db.MockDB) {
+	identity := func(_ context.Context, ids []string) ([]string, error) { return ids, nil }
+	mock.EXPECT().ResolveNodeIDs(gomock.Any(), gomock.Any()).DoAndReturn(identity).AnyTimes()
+	mock.EXPECT().ResolveEdgeIDs(gomock.Any(), gomock.Any()).DoAndReturn(identity).AnyTimes()
+}
+
+func floatptr(f float64) *float64 {
+	return &f
+}
+
+func countChannel(ch <-chan time.Time) int {
+	i := 0
+	for {
+		select {
+		case <-ch:
+			i += 1
+			continue
+		default:
+			return i
+		}
+	}
+}
+
+func TestController_graphChanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := db.NewMockDB(ctrl)
+	l := NewMockLayouter(ctrl)
+	c := NewController(db, l)
+	c.graphChanged(model.GraphChangeTypeNodeCreated, "1")
+	assert.Equal(t, 1, countChannel(c.graphChanges))
+	// it should never block and size should be 1
+	c.graphChanged(model.GraphChangeTypeNodeCreated, "1")
+	c.graphChanged(model.GraphChangeTypeNodeCreated, "1")
+	assert.Equal(t, 1, countChannel(c.graphChanges))
+}
+
+func TestController_NodeCompletion(t *testing.T) {
+	for _, test := range []struct {
+		Name             string
+		MockExpectations func(context.Context, db.MockDB, MockLayouter)
+	}{
+		{
+			Name: "TODO: return what is returned by DB",
+			MockExpectations: func(ctx context.Context, mockDB db.MockDB, mockLayouter MockLayouter) {
+				mockDB.EXPECT().NodeMatchFuzzy(gomock.Any(), "test", nil).Return([]*model.Node{{ID: "123"}}, nil)
+			},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db := db.NewMockDB(ctrl)
+			l := NewMockLayouter(ctrl)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			test.MockExpectations(ctx, *db, *l)
+			c := NewController(db, l)
+			res, err := c.NodeCompletion(ctx, "test", nil)
+			assert := assert.New(t)
+			assert.NoError(err)
+			assert.Equal(res, []*model.Node{{ID: "123"}})
+		})
+	}
+}
+
+func TestController_Search(t *testing.T) {
+	intptr := func(i int) *int { return &i }
+	german := "de"
+	hits := []*model.SearchHit{{Node: &model.Node{ID: "1"}, Rank: 0.5, DescriptionSnippet: "<b>a</b>"}}
+	for _, test := range []struct {
+		Name             string
+		Query            string
+		Language         *string
+		Limit, Offset    *int
+		MockExpectations func(context.Context, db.MockDB)
+		ExpectRes        []*model.SearchHit
+		ExpectErr        bool
+	}{
+		{
+			Name:  "defaults to requested language and first page",
+			Query: "a",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().Search(ctx, "a", "en", defaultSearchLimit, 0).Return(hits, nil)
+			},
+			ExpectRes: hits,
+		},
+		{
+			Name:     "explicit language and pagination",
+			Query:    "a",
+			Language: &german,
+			Limit:    intptr(5),
+			Offset:   intptr(10),
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().Search(ctx, "a", "de", 5, 10).Return(hits, nil)
+			},
+			ExpectRes: hits,
+		},
+		{
+			Name:             "empty query",
+			Query:            "  ",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {},
+			ExpectRes:        []*model.SearchHit{},
+		},
+		{
+			Name:             "limit too large",
+			Query:            "a",
+			Limit:            intptr(maxSearchLimit + 1),
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {},
+			ExpectErr:        true,
+		},
+		{
+			Name:  "db error",
+			Query: "a",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().Search(ctx, "a", "en", defaultSearchLimit, 0).Return(nil, errors.New("AAA"))
+			},
+			ExpectErr: true,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db := db.NewMockDB(ctrl)
+			ctx := middleware.TestingCtxNewWithLanguage(context.Background(), "en")
+			test.MockExpectations(ctx, *db)
+			c := NewController(db, nil)
+			res, err := c.Search(ctx, test.Query, test.Language, test.Limit, test.Offset)
+			assert := assert.New(t)
+			if test.ExpectErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			assert.Equal(test.ExpectRes, res)
+		})
+	}
+}
+
+func TestController_UntranslatedNodes(t *testing.T) {
+	intptr := func(i int) *int { return &i }
+	after := "7"
+	for _, test := range []struct {
+		Name             string
+		First            *int
+		After            *string
+		MockExpectations func(context.Context, db.MockDB)
+		ExpectRes        []*model.Node
+		ExpectErr        bool
+	}{
+		{
+			Name: "first page",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().UntranslatedNodes(ctx, "de", defaultUntranslatedNodesPageSize, "").Return([]*model.Node{{ID: "1"}}, nil)
+			},
+			ExpectRes: []*model.Node{{ID: "1"}},
+		},
+		{
+			Name:  "next page",
+			First: intptr(2),
+			After: &after,
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().UntranslatedNodes(ctx, "de", 2, "7").Return([]*model.Node{{ID: "8"}, {ID: "9"}}, nil)
+			},
+			ExpectRes: []*model.Node{{ID: "8"}, {ID: "9"}},
+		},
+		{
+			Name:             "invalid page size",
+			First:            intptr(-1),
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {},
+			ExpectErr:        true,
+		},
+		{
+			Name: "db error",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().UntranslatedNodes(ctx, "de", defaultUntranslatedNodesPageSize, "").Return(nil, errors.New("AAA"))
+			},
+			ExpectErr: true,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db := db.NewMockDB(ctrl)
+			ctx := context.Background()
+			test.MockExpectations(ctx, *db)
+			c := NewController(db, nil)
+			res, err := c.UntranslatedNodes(ctx, "de", test.First, test.After)
+			assert := assert.New(t)
+			if test.ExpectErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			assert.Equal(test.ExpectRes, res)
+		})
+	}
+}
+
+func TestController_EdgeVoteStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := db.NewMockDB(ctrl)
+	expectNoRedirects(mock)
+	ctx := context.Background()
+	stats := &model.EdgeVoteStats{Count: 1, Mean: floatptr(5), Median: floatptr(5), StdDev: floatptr(0), Histogram: []*model.VoteBucket{{From: 5, To: 6, Count: 1}}}
+	mock.EXPECT().EdgeVoteStats(ctx, "1").Return(stats, nil)
+	mock.EXPECT().EdgeVoteStats(ctx, "2").Return(nil, errors.New("no edge"))
+	c := NewController(mock, nil)
+	assert := assert.New(t)
+	res, err := c.EdgeVoteStats(ctx, "1")
+	assert.NoError(err)
+	assert.Equal(stats, res)
+	res, err = c.EdgeVoteStats(ctx, "2")
+	assert.Error(err)
+	assert.Nil(res)
+}
+
+func TestController_MyVotes(t *testing.T) {
+	for _, test := range []struct {
+		Name             string
+		MockExpectations func(context.Context, db.MockDB)
+		ExpectRes        []*model.EdgeVote
+		ExpectErr        bool
+	}{
+		{
+			Name: "user authenticated",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(true, &user444, nil)
+				mock.EXPECT().EdgeVotes(ctx, user444).Return([]*model.EdgeVote{{EdgeID: "1", Weight: 5}}, nil)
+			},
+			ExpectRes: []*model.EdgeVote{{EdgeID: "1", Weight: 5}},
+		},
+		{
+			Name: "user not authenticated",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(gomock.Any()).Return(false, nil, nil)
+			},
+			ExpectErr: true,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db := db.NewMockDB(ctrl)
+			ctx := context.Background()
+			test.MockExpectations(ctx, *db)
+			c := NewController(db, nil)
+			res, err := c.MyVotes(ctx)
+			assert := assert.New(t)
+			if test.ExpectErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			assert.Equal(test.ExpectRes, res)
+		})
+	}
+}
+
+func TestController_MyVote(t *testing.T) {
+	for _, test := range []struct {
+		Name             string
+		Authentication   string
+		MockExpectations func(context.Context, db.MockDB)
+		ExpectRes        map[string]*float64
+	}{
+		{
+			Name:             "no authentication",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {},
+			ExpectRes:        map[string]*float64{"1": nil, "2": nil},
+		},
+		{
+			Name:           "votes of all edges are loaded at once",
+			Authentication: "token",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(ctx).Return(true, &user444, nil)
+				mock.EXPECT().EdgeVotes(ctx, user444).Return([]*model.EdgeVote{{EdgeID: "1", Weight: 5}, {EdgeID: "3", Weight: 1}}, nil)
+			},
+			ExpectRes: map[string]*float64{"1": floatptr(5), "2": nil},
+		},
+		{
+			Name:           "user not authenticated",
+			Authentication: "expired",
+			MockExpectations: func(ctx context.Context, mock db.MockDB) {
+				mock.EXPECT().IsUserAuthenticated(ctx).Return(false, nil, nil)
+			},
+			ExpectRes: map[string]*float64{"1": nil, "2": nil},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mock := db.NewMockDB(ctrl)
+			c := NewController(mock, nil)
+			ctx := context.Background()
+			if test.Authentication != "" {
+				ctx = middleware.TestingCtxNewWithAuthentication(ctx, test.Authentication)
+			}
+			ctx = c.WithLoaders(ctx)
+			test.MockExpectations(ctx, *mock)
+			assert := assert.New(t)
+			mu := sync.Mutex{}
+			res := map[string]*float64{}
+			wg := sync.WaitGroup{}
+			for _, id := range []string{"1", "2"} {
+				wg.Add(1)
+				go func(id string) {
+					defer wg.Done()
+					vote, err := c.MyVote(ctx, id)
+					assert.NoError(err)
+					mu.Lock()
+					res[id] = vote
+					mu.Unlock()
+				}(id)
+			}
+			wg.Wait()
+			assert.Equal(test.ExpectRes, res)
+		})
+	}
+}
+
+func TestController_LearningPath(t *testing.T) {
 	for _, test := range []struct {
 		Name             string
 		MockExpectations func(context.Context, db.MockDB, MockLayouter)
-		Setup            func(trigger chan time.Time)
+		ExpectGraph      *model.Graph
+		ExpectErr        bool
 	}{
 		{
-			Name: "should run layout on startup",
+			Name: "path found, positions added",
 			MockExpectations: func(ctx context.Context, mockDB db.MockDB, mockLayouter MockLayouter) {
-				mockDB.EXPECT().Graph(gomock.Any()).Return(&model.Graph{Nodes: []*model.Node{{}, {}}}, nil)
-				mockLayouter.EXPECT().Reload(gomock.Any(), &model.Graph{Nodes: []*model.Node{{}, {}}}).Return(layout.Stats{Iterations: 5})
+				mockDB.EXPECT().Graph(ctx, prerequisitesOnly).Return(&model.Graph{
+					Nodes: []*model.Node{{ID: "1"}, {ID: "2"}, {ID: "3"}},
+					Edges: []*model.Edge{{ID: "4", From: "1", To: "2", Weight: 5}},
+				}, nil)
+				mockLayouter.EXPECT().GetNodePositions(ctx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, g *model.Graph) {
+						for _, node := range g.Nodes {
+							node.Position = &model.Vector{X: 1}
+						}
+					},
+				)
+			},
+			ExpectGraph: &model.Graph{
+				Nodes: []*model.Node{{ID: "1", Position: &model.Vector{X: 1}}, {ID: "2", Position: &model.Vector{X: 1}}},
+				Edges: []*model.Edge{{ID: "4", From: "1", To: "2", Weight: 5}},
 			},
 		},
 		{
-			Name: "should run layout on trigger call",
+			Name: "no path",
 			MockExpectations: func(ctx context.Context, mockDB db.MockDB, mockLayouter MockLayouter) {
-				mockDB.EXPECT().Graph(gomock.Any()).Return(&model.Graph{Nodes: []*model.Node{{}, {}}}, nil)
-				mockLayouter.EXPECT().Reload(gomock.Any(), &model.Graph{Nodes: []*model.Node{{}, {}}}).Return(layout.Stats{Iterations: 5})
-				// 2nd call
-				mockDB.EXPECT().Graph(gomock.Any()).Return(&model.Graph{Nodes: []*model.Node{{}, {}}}, nil)
-				mockLayouter.EXPECT().Reload(gomock.Any(), &model.Graph{Nodes: []*model.Node{{}, {}}}).Return(layout.Stats{Iterations: 5})
+				mockDB.EXPECT().Graph(ctx, prerequisitesOnly).Return(&model.Graph{
+					Nodes: []*model.Node{{ID: "1"}, {ID: "2"}},
+				}, nil)
 			},
-			Setup: func(trigger chan time.Time) {
-				trigger <- time.UnixMilli(7)
+			ExpectErr: true,
+		},
+		{
+			Name: "db error",
+			MockExpectations: func(ctx context.Context, mockDB db.MockDB, mockLayouter MockLayouter) {
+				mockDB.EXPECT().Graph(ctx, prerequisitesOnly).Return(nil, errors.New("AAA"))
 			},
+			ExpectErr: true,
 		},
 	} {
 		t.Run(test.Name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			db := db.NewMockDB(ctrl)
+			expectNoRedirects(db)
 			l := NewMockLayouter(ctrl)
-			ctx, cancel := context.WithCancel(context.Background())
-			defer cancel()
+			ctx := context.Background()
 			test.MockExpectations(ctx, *db, *l)
 			c := NewController(db, l)
-			trigger := make(chan time.Time, 10)
-			if test.Setup != nil {
-				test.Setup(trigger)
+			graph, err := c.LearningPath(ctx, "1", "2")
+			assert := assert.New(t)
+			if test.ExpectErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
 			}
-			go c.periodicGraphEmbeddingComputation(ctx, trigger, time.Second*1)
-			time.Sleep(time.Millisecond * 10) // XXX(skep): could be a flaky test some day: can we do it without sleeping?
+			assert.Equal(test.ExpectGraph, graph)
 		})
 	}
 }
 
-func countChannel(ch <-chan time.Time) int {
-	i := 0
-	for {
-		select {
-		case <-ch:
-			i += 1
-			continue
-		default:
-			return i
-		}
+func TestController_Prerequisites(t *testing.T) {
+	maxDepth1 := 1
+	for _, test := range []struct {
+		Name             string
+		MockExpectations func(context.Context, db.MockDB, MockLayouter)
+		MaxDepth         *int
+		ExpectGraph      *model.Graph
+		ExpectErr        bool
+	}{
+		{
+			Name: "closure with positions",
+			MockExpectations: func(ctx context.Context, mockDB db.MockDB, mockLayouter MockLayouter) {
+				mockDB.EXPECT().Graph(ctx, prerequisitesOnly).Return(&model.Graph{
+					Nodes: []*model.Node{{ID: "1"}, {ID: "2"}, {ID: "3"}},
+					Edges: []*model.Edge{{ID: "4", From: "3", To: "1", Weight: 5}, {ID: "5", From: "2", To: "3", Weight: 5}},
+				}, nil)
+				mockLayouter.EXPECT().GetNodePositions(ctx, gomock.Any())
+			},
+			ExpectGraph: &model.Graph{
+				Nodes: []*model.Node{{ID: "2"}, {ID: "3"}, {ID: "1"}},
+				Edges: []*model.Edge{{ID: "5", From: "2", To: "3", Weight: 5}, {ID: "4", From: "3", To: "1", Weight: 5}},
+			},
+		},
+		{
+			Name: "closure with max depth",
+			MockExpectations: func(ctx context.Context, mockDB db.MockDB, mockLayouter MockLayouter) {
+				mockDB.EXPECT().Graph(ctx, prerequisitesOnly).Return(&model.Graph{
+					Nodes: []*model.Node{{ID: "1"}, {ID: "2"}, {ID: "3"}},
+					Edges: []*model.Edge{{ID: "4", From: "3", To: "1", Weight: 5}, {ID: "5", From: "2", To: "3", Weight: 5}},
+				}, nil)
+				mockLayouter.EXPECT().GetNodePositions(ctx, gomock.Any())
+			},
+			MaxDepth: &maxDepth1,
+			ExpectGraph: &model.Graph{
+				Nodes: []*model.Node{{ID: "3"}, {ID: "1"}},
+				Edges: []*model.Edge{{ID: "4", From: "3", To: "1", Weight: 5}},
+			},
+		},
+		{
+			Name: "unknown node",
+			MockExpectations: func(ctx context.Context, mockDB db.MockDB, mockLayouter MockLayouter) {
+				mockDB.EXPECT().Graph(ctx, prerequisitesOnly).Return(&model.Graph{}, nil)
+			},
+			ExpectErr: true,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db := db.NewMockDB(ctrl)
+			expectNoRedirects(db)
+			l := NewMockLayouter(ctrl)
+			ctx := context.Background()
+			test.MockExpectations(ctx, *db, *l)
+			c := NewController(db, l)
+			graph, err := c.Prerequisites(ctx, "1", test.MaxDepth, nil)
+			assert := assert.New(t)
+			if test.ExpectErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			assert.Equal(test.ExpectGraph, graph)
+		})
 	}
 }
 
-func TestController_graphChanged(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	db := db.NewMockDB(ctrl)
-	l := NewMockLayouter(ctrl)
-	c := NewController(db, l)
-	c.graphChanged()
-	assert.Equal(t, 1, countChannel(c.graphChanges))
-	// it should never block and size should be 1
-	c.graphChanged()
-	c.graphChanged()
-	assert.Equal(t, 1, countChannel(c.graphChanges))
+func TestController_Subgraph(t *testing.T) {
+	for _, test := range []struct {
+		Name             string
+		MockExpectations func(context.Context, db.MockDB, MockLayouter)
+		ExpectGraph      *model.Graph
+		ExpectErr        bool
+	}{
+		{
+			Name: "positions added",
+			MockExpectations: func(ctx context.Context, mockDB db.MockDB, mockLayouter MockLayouter) {
+				mockDB.EXPECT().Subgraph(ctx, "1", 2, model.DirectionBoth).Return(&model.Graph{
+					Nodes: []*model.Node{{ID: "1"}},
+				}, nil)
+				mockLayouter.EXPECT().GetNodePositions(ctx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, g *model.Graph) {
+						g.Nodes[0].Position = &model.Vector{X: 1, Y: 2, Z: 3}
+					},
+				)
+			},
+			ExpectGraph: &model.Graph{Nodes: []*model.Node{{ID: "1", Position: &model.Vector{X: 1, Y: 2, Z: 3}}}},
+		},
+		{
+			Name: "db error",
+			MockExpectations: func(ctx context.Context, mockDB db.MockDB, mockLayouter MockLayouter) {
+				mockDB.EXPECT().Subgraph(ctx, "1", 2, model.DirectionBoth).Return(nil, errors.New("AAA"))
+			},
+			ExpectErr: true,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db := db.NewMockDB(ctrl)
+			expectNoRedirects(db)
+			l := NewMockLayouter(ctrl)
+			ctx := context.Background()
+			test.MockExpectations(ctx, *db, *l)
+			c := NewController(db, l)
+			graph, err := c.Subgraph(ctx, "1", 2, model.DirectionBoth)
+			assert := assert.New(t)
+			if test.ExpectErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			assert.Equal(test.ExpectGraph, graph)
+		})
+	}
 }
 
-func TestController_NodeCompletion(t *testing.T) {
+func TestController_GraphInViewport(t *testing.T) {
+	zoom := 0.5
 	for _, test := range []struct {
 		Name             string
 		MockExpectations func(context.Context, db.MockDB, MockLayouter)
+		MaxX             float64
+		Zoom             *float64
+		ExpectGraph      *model.Graph
+		ExpectErr        bool
 	}{
 		{
-			Name: "TODO: return what is returned by DB",
+			Name: "nodes in viewport",
+			MockExpectations: func(ctx context.Context, mockDB db.MockDB, mockLayouter MockLayouter) {
+				mockLayouter.EXPECT().NodesInViewport(ctx, layout.Rect{X: 0, Y: 0, Width: 10, Height: 10}, 1.0).Return([]string{"1"})
+				mockDB.EXPECT().GraphOfNodes(ctx, []string{"1"}).Return(&model.Graph{Nodes: []*model.Node{{ID: "1"}}}, nil)
+				mockLayouter.EXPECT().GetNodePositions(ctx, gomock.Any())
+			},
+			MaxX:        10,
+			ExpectGraph: &model.Graph{Nodes: []*model.Node{{ID: "1"}}},
+		},
+		{
+			Name: "zoom passed to layouter",
 			MockExpectations: func(ctx context.Context, mockDB db.MockDB, mockLayouter MockLayouter) {
-				mockDB.EXPECT().NodeMatchFuzzy(gomock.Any(), "test").Return([]*model.Node{{ID: "123"}}, nil)
+				mockLayouter.EXPECT().NodesInViewport(ctx, layout.Rect{X: 0, Y: 0, Width: 10, Height: 10}, 0.5).Return([]string{})
+				mockDB.EXPECT().GraphOfNodes(ctx, []string{}).Return(&model.Graph{}, nil)
+				mockLayouter.EXPECT().GetNodePositions(ctx, gomock.Any())
 			},
+			MaxX:        10,
+			Zoom:        &zoom,
+			ExpectGraph: &model.Graph{},
+		},
+		{
+			Name:             "invalid viewport",
+			MockExpectations: func(ctx context.Context, mockDB db.MockDB, mockLayouter MockLayouter) {},
+			MaxX:             -10,
+			ExpectErr:        true,
 		},
 	} {
 		t.Run(test.Name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			db := db.NewMockDB(ctrl)
 			l := NewMockLayouter(ctrl)
-			ctx, cancel := context.WithCancel(context.Background())
-			defer cancel()
+			ctx := context.Background()
 			test.MockExpectations(ctx, *db, *l)
 			c := NewController(db, l)
-			res, err := c.NodeCompletion(ctx, "test")
+			graph, err := c.GraphInViewport(ctx, 0, 0, test.MaxX, 10, test.Zoom)
 			assert := assert.New(t)
-			assert.NoError(err)
-			assert.Equal(res, []*model.Node{{ID: "123"}})
+			if test.ExpectErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			assert.Equal(test.ExpectGraph, graph)
+		})
+	}
+}
+
+func TestController_periodicTrashPurge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := db.NewMockDB(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	now := time.UnixMilli(1000000)
+	db.EXPECT().PurgeTrash(gomock.Any(), now.Add(-time.Minute)).Return(nil)
+	c := NewController(db, nil)
+	trigger := make(chan time.Time, 1)
+	trigger <- now
+	go c.periodicTrashPurge(ctx, trigger, time.Minute)
+	time.Sleep(time.Millisecond * 10) // XXX(skep): could be a flaky test some day: can we do it without sleeping?
+}
+
+func TestController_aggregateVotes(t *testing.T) {
+	fullBatch := make([]string, voteAggregationBatchSize)
+	for i := range fullBatch {
+		fullBatch[i] = fmt.Sprint(i)
+	}
+	for _, test := range []struct {
+		Name               string
+		MockExpectations   func(mock *db.MockDB)
+		ExpectGraphChanged int
+	}{
+		{
+			Name: "nothing to aggregate",
+			MockExpectations: func(mock *db.MockDB) {
+				mock.EXPECT().AggregateDirtyEdges(gomock.Any(), voteAggregationBatchSize).Return([]string{}, nil)
+			},
+		},
+		{
+			Name: "single batch",
+			MockExpectations: func(mock *db.MockDB) {
+				mock.EXPECT().AggregateDirtyEdges(gomock.Any(), voteAggregationBatchSize).Return([]string{"1", "2"}, nil)
+			},
+			ExpectGraphChanged: 1,
+		},
+		{
+			Name: "full batch is followed by another batch",
+			MockExpectations: func(mock *db.MockDB) {
+				gomock.InOrder(
+					mock.EXPECT().AggregateDirtyEdges(gomock.Any(), voteAggregationBatchSize).Return(fullBatch, nil),
+					mock.EXPECT().AggregateDirtyEdges(gomock.Any(), voteAggregationBatchSize).Return([]string{"1"}, nil),
+				)
+			},
+			ExpectGraphChanged: 1,
+		},
+		{
+			Name: "db error",
+			MockExpectations: func(mock *db.MockDB) {
+				mock.EXPECT().AggregateDirtyEdges(gomock.Any(), voteAggregationBatchSize).Return(nil, errors.New("fail"))
+			},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db := db.NewMockDB(ctrl)
+			test.MockExpectations(db)
+			c := NewController(db, nil)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			events := c.graphEvents.subscribe(ctx)
+			c.aggregateVotes(ctx)
+			assert.Equal(t, test.ExpectGraphChanged, countChannel(c.graphChanges))
+			if test.ExpectGraphChanged > 0 {
+				event, ok := receiveWithTimeout(t, events)
+				assert.True(t, ok)
+				assert.Equal(t, model.GraphChangeTypeEdgeVoted, event.Type)
+			}
 		})
 	}
 }
@@ -0,0 +1,252 @@
+// Package graphalgo implements graph algorithms on top of model.Graph.
+//
+// All functions work on an in-memory graph and do not depend on any database
+// backend, such that they can be tested in isolation.
+package graphalgo
+
+import (
+	"container/heap"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/suxatcode/learn-graph-poc-backend/graph/model"
+)
+
+var (
+	ErrNoPath = errors.New("no path found")
+)
+
+// adjacency maps a node ID to the edges connected to it.
+type adjacency map[string][]*model.Edge
+
+func outgoingEdges(g *model.Graph) adjacency {
+	adj := make(adjacency, len(g.Nodes))
+	for _, edge := range g.Edges {
+		adj[edge.From] = append(adj[edge.From], edge)
+	}
+	return adj
+}
+
+func incomingEdges(g *model.Graph) adjacency {
+	adj := make(adjacency, len(g.Nodes))
+	for _, edge := range g.Edges {
+		adj[edge.To] = append(adj[edge.To], edge)
+	}
+	return adj
+}
+
+func nodesByID(g *model.Graph) map[string]*model.Node {
+	nodes := make(map[string]*model.Node, len(g.Nodes))
+	for _, node := range g.Nodes {
+		nodes[node.ID] = node
+	}
+	return nodes
+}
+
+// edgeCost converts an edge weight into a path cost: strong prerequisite
+// relations (high weight) are cheap to follow, weak ones are expensive.
+// Edges with a non-positive weight do not describe a dependency and are not
+// traversable.
+func edgeCost(edge *model.Edge) (float64, bool) {
+	if edge.Weight <= 0 {
+		return 0, false
+	}
+	return 1 / edge.Weight, true
+}
+
+// LearningPath returns the nodes and edges along the cheapest path from node
+// `from` to node `to`, following edges in their direction. Nodes and edges of
+// the returned graph are ordered along the path, starting at `from`.
+func LearningPath(g *model.Graph, from, to string) (*model.Graph, error) {
+	nodes := nodesByID(g)
+	if _, ok := nodes[from]; !ok {
+		return nil, errors.Errorf("node '%s' does not exist", from)
+	}
+	if _, ok := nodes[to]; !ok {
+		return nil, errors.Errorf("node '%s' does not exist", to)
+	}
+	adj := outgoingEdges(g)
+	cost := map[string]float64{from: 0}
+	via := map[string]*model.Edge{}
+	done := map[string]bool{}
+	queue := &priorityQueue{{node: from, cost: 0}}
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(*queueItem)
+		if done[current.node] {
+			continue
+		}
+		done[current.node] = true
+		if current.node == to {
+			break
+		}
+		for _, edge := range adj[current.node] {
+			c, ok := edgeCost(edge)
+			if !ok || done[edge.To] {
+				continue
+			}
+			if known, exists := cost[edge.To]; exists && known <= current.cost+c {
+				continue
+			}
+			cost[edge.To] = current.cost + c
+			via[edge.To] = edge
+			heap.Push(queue, &queueItem{node: edge.To, cost: current.cost + c})
+		}
+	}
+	if !done[to] {
+		return nil, errors.Wrapf(ErrNoPath, "from '%s' to '%s'", from, to)
+	}
+	path := &model.Graph{Nodes: []*model.Node{nodes[to]}}
+	for node := to; node != from; {
+		edge := via[node]
+		path.Nodes = append(path.Nodes, nodes[edge.From])
+		path.Edges = append(path.Edges, edge)
+		node = edge.From
+	}
+	reverse(path.Nodes)
+	reverse(path.Edges)
+	return path, nil
+}
+
+// PrerequisitesConfig limits the traversal of Prerequisites.
+type PrerequisitesConfig struct {
+	// MaxDepth limits the number of edges walked from the target node, a
+	// value <= 0 means unlimited.
+	MaxDepth int
+	// MinWeight ignores all edges with a lower weight.
+	MinWeight float64
+}
+
+// Prerequisites returns all nodes, that transitively lead to node `nodeID`
+// via incoming edges, together with the edges between them. The nodes are in
+// topological order, i.e. every node is listed after all of its
+// prerequisites, thus `nodeID` comes last.
+// Should the prerequisites contain cycles, the nodes of a cycle are appended
+// in order of decreasing distance to `nodeID`.
+func Prerequisites(g *model.Graph, nodeID string, conf PrerequisitesConfig) (*model.Graph, error) {
+	nodes := nodesByID(g)
+	if _, ok := nodes[nodeID]; !ok {
+		return nil, errors.Errorf("node '%s' does not exist", nodeID)
+	}
+	adj := incomingEdges(g)
+	depth := map[string]int{nodeID: 0}
+	queue := []string{nodeID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if conf.MaxDepth > 0 && depth[current] >= conf.MaxDepth {
+			continue
+		}
+		for _, edge := range adj[current] {
+			if edge.Weight < conf.MinWeight {
+				continue
+			}
+			if _, seen := depth[edge.From]; seen {
+				continue
+			}
+			depth[edge.From] = depth[current] + 1
+			queue = append(queue, edge.From)
+		}
+	}
+	closure := &model.Graph{Nodes: []*model.Node{}, Edges: []*model.Edge{}}
+	for _, edge := range g.Edges {
+		_, fromInClosure := depth[edge.From]
+		_, toInClosure := depth[edge.To]
+		if fromInClosure && toInClosure && edge.Weight >= conf.MinWeight {
+			closure.Edges = append(closure.Edges, edge)
+		}
+	}
+	order := topologicalOrder(depth, closure.Edges)
+	position := make(map[string]int, len(order))
+	for i, id := range order {
+		position[id] = i
+		closure.Nodes = append(closure.Nodes, nodes[id])
+	}
+	sort.SliceStable(closure.Edges, func(i, j int) bool {
+		a, b := closure.Edges[i], closure.Edges[j]
+		if position[a.To] == position[b.To] {
+			return position[a.From] < position[b.From]
+		}
+		return position[a.To] < position[b.To]
+	})
+	return closure, nil
+}
+
+// topologicalOrder sorts the nodes in `depth` using Kahn's algorithm, ties are
+// broken by higher depth first and then by node ID.
+func topologicalOrder(depth map[string]int, edges []*model.Edge) []string {
+	less := func(a, b string) bool {
+		if depth[a] == depth[b] {
+			return a < b
+		}
+		return depth[a] > depth[b]
+	}
+	inDegree := make(map[string]int, len(depth))
+	outgoing := make(map[string][]string, len(depth))
+	for _, edge := range edges {
+		inDegree[edge.To] += 1
+		outgoing[edge.From] = append(outgoing[edge.From], edge.To)
+	}
+	ready := []string{}
+	for id := range depth {
+		if inDegree[id] == 0 {
+			ready = append(ready, id)
+		}
+	}
+	order := make([]string, 0, len(depth))
+	done := make(map[string]bool, len(depth))
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool { return less(ready[i], ready[j]) })
+		current := ready[0]
+		ready = ready[1:]
+		order = append(order, current)
+		done[current] = true
+		for _, next := range outgoing[current] {
+			inDegree[next] -= 1
+			if inDegree[next] == 0 {
+				ready = append(ready, next)
+			}
+		}
+	}
+	if len(order) < len(depth) {
+		rest := []string{}
+		for id := range depth {
+			if !done[id] {
+				rest = append(rest, id)
+			}
+		}
+		sort.Slice(rest, func(i, j int) bool { return less(rest[i], rest[j]) })
+		order = append(order, rest...)
+	}
+	return order
+}
+
+func reverse[T any](ts []T) {
+	for i, j := 0, len(ts)-1; i < j; i, j = i+1, j-1 {
+		ts[i], ts[j] = ts[j], ts[i]
+	}
+}
+
+type queueItem struct {
+	node string
+	cost float64
+}
+
+// priorityQueue implements heap.Interface, lowest cost first
+type priorityQueue []*queueItem
+
+func (pq priorityQueue) Len() int { return len(pq) }
+func (pq priorityQueue) Less(i, j int) bool {
+	if pq[i].cost == pq[j].cost {
+		return pq[i].node < pq[j].node
+	}
+	return pq[i].cost < pq[j].cost
+}
+func (pq priorityQueue) Swap(i, j int)       { pq[i], pq[j] = pq[j], pq[i] }
+func (pq *priorityQueue) Push(x interface{}) { *pq = append(*pq, x.(*queueItem)) }
+func (pq *priorityQueue) Pop() interface{} {
+	old := *pq
+	n := len(old)
+	item := old[n-1]
+	*pq = old[:n-1]
+	return item
+}
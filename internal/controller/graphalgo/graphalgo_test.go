@@ -0,0 +1,230 @@
+package graphalgo
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/suxatcode/learn-graph-poc-backend/graph/model"
+)
+
+func nodeIDs(nodes []*model.Node) []string {
+	ids := []string{}
+	for _, node := range nodes {
+		ids = append(ids, node.ID)
+	}
+	return ids
+}
+
+func edgeIDs(edges []*model.Edge) []string {
+	ids := []string{}
+	for _, edge := range edges {
+		ids = append(ids, edge.ID)
+	}
+	return ids
+}
+
+func TestLearningPath(t *testing.T) {
+	for _, test := range []struct {
+		Name            string
+		Graph           *model.Graph
+		From, To        string
+		ExpectNodes     []string
+		ExpectEdges     []string
+		ExpectErr       bool
+		ExpectErrNoPath bool
+	}{
+		{
+			Name: "direct edge",
+			Graph: &model.Graph{
+				Nodes: []*model.Node{{ID: "1"}, {ID: "2"}},
+				Edges: []*model.Edge{{ID: "10", From: "1", To: "2", Weight: 5}},
+			},
+			From: "1", To: "2",
+			ExpectNodes: []string{"1", "2"},
+			ExpectEdges: []string{"10"},
+		},
+		{
+			Name: "same node",
+			Graph: &model.Graph{
+				Nodes: []*model.Node{{ID: "1"}, {ID: "2"}},
+				Edges: []*model.Edge{{ID: "10", From: "1", To: "2", Weight: 5}},
+			},
+			From: "1", To: "1",
+			ExpectNodes: []string{"1"},
+			ExpectEdges: []string{},
+		},
+		{
+			Name: "prefer strong edges over few edges",
+			Graph: &model.Graph{
+				Nodes: []*model.Node{{ID: "1"}, {ID: "2"}, {ID: "3"}},
+				Edges: []*model.Edge{
+					{ID: "10", From: "1", To: "3", Weight: 1},  // cost 1
+					{ID: "11", From: "1", To: "2", Weight: 10}, // cost 0.1
+					{ID: "12", From: "2", To: "3", Weight: 10}, // cost 0.1
+				},
+			},
+			From: "1", To: "3",
+			ExpectNodes: []string{"1", "2", "3"},
+			ExpectEdges: []string{"11", "12"},
+		},
+		{
+			Name: "edges are directed",
+			Graph: &model.Graph{
+				Nodes: []*model.Node{{ID: "1"}, {ID: "2"}},
+				Edges: []*model.Edge{{ID: "10", From: "1", To: "2", Weight: 5}},
+			},
+			From: "2", To: "1",
+			ExpectErr:       true,
+			ExpectErrNoPath: true,
+		},
+		{
+			Name: "zero weight edges are not traversed",
+			Graph: &model.Graph{
+				Nodes: []*model.Node{{ID: "1"}, {ID: "2"}},
+				Edges: []*model.Edge{{ID: "10", From: "1", To: "2", Weight: 0}},
+			},
+			From: "1", To: "2",
+			ExpectErr:       true,
+			ExpectErrNoPath: true,
+		},
+		{
+			Name: "cycles do not matter",
+			Graph: &model.Graph{
+				Nodes: []*model.Node{{ID: "1"}, {ID: "2"}, {ID: "3"}},
+				Edges: []*model.Edge{
+					{ID: "10", From: "1", To: "2", Weight: 5},
+					{ID: "11", From: "2", To: "1", Weight: 5},
+					{ID: "12", From: "2", To: "3", Weight: 5},
+				},
+			},
+			From: "1", To: "3",
+			ExpectNodes: []string{"1", "2", "3"},
+			ExpectEdges: []string{"10", "12"},
+		},
+		{
+			Name: "unknown node",
+			Graph: &model.Graph{
+				Nodes: []*model.Node{{ID: "1"}},
+			},
+			From: "1", To: "2",
+			ExpectErr: true,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+			path, err := LearningPath(test.Graph, test.From, test.To)
+			if test.ExpectErr {
+				assert.Error(err)
+				assert.Equal(test.ExpectErrNoPath, errors.Is(err, ErrNoPath))
+				return
+			}
+			if !assert.NoError(err) {
+				return
+			}
+			assert.Equal(test.ExpectNodes, nodeIDs(path.Nodes))
+			assert.Equal(test.ExpectEdges, edgeIDs(path.Edges))
+		})
+	}
+}
+
+func TestPrerequisites(t *testing.T) {
+	chain := &model.Graph{
+		Nodes: []*model.Node{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}},
+		Edges: []*model.Edge{
+			{ID: "10", From: "1", To: "2", Weight: 5},
+			{ID: "11", From: "2", To: "3", Weight: 1},
+			{ID: "12", From: "3", To: "4", Weight: 5},
+		},
+	}
+	for _, test := range []struct {
+		Name        string
+		Graph       *model.Graph
+		NodeID      string
+		Config      PrerequisitesConfig
+		ExpectNodes []string
+		ExpectEdges []string
+		ExpectErr   bool
+	}{
+		{
+			Name:        "chain, unlimited",
+			Graph:       chain,
+			NodeID:      "4",
+			ExpectNodes: []string{"1", "2", "3", "4"},
+			ExpectEdges: []string{"10", "11", "12"},
+		},
+		{
+			Name:        "chain, max depth",
+			Graph:       chain,
+			NodeID:      "4",
+			Config:      PrerequisitesConfig{MaxDepth: 2},
+			ExpectNodes: []string{"2", "3", "4"},
+			ExpectEdges: []string{"11", "12"},
+		},
+		{
+			Name:        "chain, min weight",
+			Graph:       chain,
+			NodeID:      "4",
+			Config:      PrerequisitesConfig{MinWeight: 2},
+			ExpectNodes: []string{"3", "4"},
+			ExpectEdges: []string{"12"},
+		},
+		{
+			Name:        "outgoing edges are ignored",
+			Graph:       chain,
+			NodeID:      "1",
+			ExpectNodes: []string{"1"},
+			ExpectEdges: []string{},
+		},
+		{
+			Name: "diamond: shared prerequisite comes first",
+			Graph: &model.Graph{
+				Nodes: []*model.Node{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}},
+				Edges: []*model.Edge{
+					{ID: "10", From: "2", To: "4", Weight: 5},
+					{ID: "11", From: "3", To: "4", Weight: 5},
+					{ID: "12", From: "1", To: "2", Weight: 5},
+					{ID: "13", From: "1", To: "3", Weight: 5},
+					{ID: "14", From: "2", To: "3", Weight: 5},
+				},
+			},
+			NodeID:      "4",
+			ExpectNodes: []string{"1", "2", "3", "4"},
+			ExpectEdges: []string{"12", "13", "14", "10", "11"},
+		},
+		{
+			Name: "cycle does not loop forever",
+			Graph: &model.Graph{
+				Nodes: []*model.Node{{ID: "1"}, {ID: "2"}, {ID: "3"}},
+				Edges: []*model.Edge{
+					{ID: "10", From: "1", To: "2", Weight: 5},
+					{ID: "11", From: "2", To: "1", Weight: 5},
+					{ID: "12", From: "2", To: "3", Weight: 5},
+				},
+			},
+			NodeID:      "3",
+			ExpectNodes: []string{"1", "2", "3"},
+			ExpectEdges: []string{"11", "10", "12"},
+		},
+		{
+			Name:      "unknown node",
+			Graph:     chain,
+			NodeID:    "5",
+			ExpectErr: true,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+			closure, err := Prerequisites(test.Graph, test.NodeID, test.Config)
+			if test.ExpectErr {
+				assert.Error(err)
+				return
+			}
+			if !assert.NoError(err) {
+				return
+			}
+			assert.Equal(test.ExpectNodes, nodeIDs(closure.Nodes))
+			assert.Equal(test.ExpectEdges, edgeIDs(closure.Edges))
+		})
+	}
+}
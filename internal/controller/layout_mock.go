@@ -48,6 +48,20 @@ func (mr *MockLayouterMockRecorder) GetNodePositions(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodePositions", reflect.TypeOf((*MockLayouter)(nil).GetNodePositions), arg0, arg1)
 }
 
+// NodesInViewport mocks base method.
+func (m *MockLayouter) NodesInViewport(arg0 context.Context, arg1 layout.Rect, arg2 float64) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodesInViewport", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// NodesInViewport indicates an expected call of NodesInViewport.
+func (mr *MockLayouterMockRecorder) NodesInViewport(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodesInViewport", reflect.TypeOf((*MockLayouter)(nil).NodesInViewport), arg0, arg1, arg2)
+}
+
 // Reload mocks base method.
 func (m *MockLayouter) Reload(arg0 context.Context, arg1 *model.Graph) layout.Stats {
 	m.ctrl.T.Helper()
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"math"
 	"runtime"
 
 	"github.com/rs/zerolog/log"
@@ -20,6 +21,10 @@ type Layouter interface {
 	// Reload re-runs graph embedding. This is a synchronous call and will
 	// take some time.
 	Reload(context.Context, *model.Graph) layout.Stats
+	// NodesInViewport returns the IDs of all nodes positioned inside of the
+	// viewport by the last graph embedding run. A zoom below 1 hides nodes
+	// with few edges, see minDegreeForZoom.
+	NodesInViewport(ctx context.Context, viewport layout.Rect, zoom float64) []string
 }
 
 // NewLayouter returns an implementation of the Layouter interface.
@@ -47,6 +52,62 @@ type simulationState struct {
 	ledges                  []*layout.Edge
 	modelToLayoutNodeLookup map[string]int
 	modelToLayoutEdgeLookup map[string]int
+	// spatialIndex is built after each complete simulation and is nil before.
+	spatialIndex *spatialIndex
+}
+
+// spatialIndex allows quick lookup of nodes by position.
+type spatialIndex struct {
+	tree   *layout.QuadTree
+	nodeID map[*layout.Node]string
+	degree map[*layout.Node]int
+}
+
+func newSpatialIndex(s *simulationState) *spatialIndex {
+	index := &spatialIndex{
+		nodeID: make(map[*layout.Node]string, len(s.lnodes)),
+		degree: make(map[*layout.Node]int, len(s.lnodes)),
+	}
+	for id, idx := range s.modelToLayoutNodeLookup {
+		index.nodeID[s.lnodes[idx]] = id
+	}
+	for _, edge := range s.ledges {
+		index.degree[s.lnodes[edge.Source]] += 1
+		index.degree[s.lnodes[edge.Target]] += 1
+	}
+	index.tree = layout.NewQuadTree(&layout.QuadTreeConfig{CapacityOfEachBlock: 10}, nil, boundingRect(s.lnodes))
+	for _, node := range s.lnodes {
+		index.tree.Insert(node)
+	}
+	return index
+}
+
+// boundingRect returns the smallest Rect containing all nodes, ignoring nodes
+// without a valid position.
+func boundingRect(nodes []*layout.Node) layout.Rect {
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, node := range nodes {
+		x, y := node.Pos.X(), node.Pos.Y()
+		if math.IsNaN(x) || math.IsNaN(y) {
+			continue
+		}
+		minX, minY = math.Min(minX, x), math.Min(minY, y)
+		maxX, maxY = math.Max(maxX, x), math.Max(maxY, y)
+	}
+	if minX > maxX {
+		return layout.Rect{}
+	}
+	return layout.Rect{X: minX, Y: minY, Width: maxX - minX, Height: maxY - minY}
+}
+
+// minDegreeForZoom returns the minimum number of edges a node needs to be
+// visible at the given zoom: all nodes are visible at zoom >= 1, each halving
+// of the zoom hides nodes with one more edge.
+func minDegreeForZoom(zoom float64) int {
+	if zoom >= 1 || zoom <= 0 {
+		return 0
+	}
+	return int(math.Floor(math.Log2(1 / zoom)))
 }
 
 func NewForceSimulationLayouter() *ForceSimulationLayouter {
@@ -178,6 +239,7 @@ func (l *ForceSimulationLayouter) Reload(ctx context.Context, g *model.Graph) la
 	l.completeSimulation.InitializeNodes(ctx, s.lnodes)
 	_, stats := l.completeSimulation.ComputeLayout(ctx, s.lnodes, s.ledges)
 	l.updateGraphWithPositions(&s, g)
+	s.spatialIndex = newSpatialIndex(&s)
 	l.simulationState = &s
 	if !l.initialLayoutDone {
 		l.initialLayoutDone = true
@@ -186,6 +248,24 @@ func (l *ForceSimulationLayouter) Reload(ctx context.Context, g *model.Graph) la
 	return stats
 }
 
+func (l *ForceSimulationLayouter) NodesInViewport(ctx context.Context, viewport layout.Rect, zoom float64) []string {
+	<-l.waitForInitialLayout
+	index := l.simulationState.spatialIndex
+	ids := []string{}
+	if index == nil {
+		return ids
+	}
+	minDegree := minDegreeForZoom(zoom)
+	for _, node := range index.tree.Query(viewport) {
+		id, ok := index.nodeID[node]
+		if !ok || index.degree[node] < minDegree {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
 func (l *ForceSimulationLayouter) updateGraphWithPositions(s *simulationState, g *model.Graph) {
 	for i := range g.Nodes {
 		idx := s.modelToLayoutNodeLookup[g.Nodes[i].ID]
@@ -194,6 +274,16 @@ func (l *ForceSimulationLayouter) updateGraphWithPositions(s *simulationState, g
 	}
 }
 
+// edgeTypeAttraction scales the attraction along an edge by its type, such
+// that loosely related nodes are pulled together less than prerequisites.
+// Unknown types default to an attraction of 1 (see layout.NewGraph).
+var edgeTypeAttraction = map[model.EdgeType]float64{
+	model.EdgeTypePrerequisite: 1.0,
+	model.EdgeTypePartOf:       1.0,
+	model.EdgeTypeExampleOf:    0.5,
+	model.EdgeTypeRelated:      0.25,
+}
+
 // returns newly added nodes and edges as layout.{Node/Edge} type
 func appendNodesAndEdges(s *simulationState, nodes []*model.Node, edges []*model.Edge) ([]*layout.Node, []*layout.Edge) {
 	newNodes := []*layout.Node{}
@@ -203,7 +293,11 @@ func appendNodesAndEdges(s *simulationState, nodes []*model.Node, edges []*model
 	}
 	newEdges := []*layout.Edge{}
 	for index, edge := range edges {
-		newEdges = append(newEdges, &layout.Edge{Source: s.modelToLayoutNodeLookup[edge.From], Target: s.modelToLayoutNodeLookup[edge.To]})
+		newEdges = append(newEdges, &layout.Edge{
+			Source: s.modelToLayoutNodeLookup[edge.From],
+			Target: s.modelToLayoutNodeLookup[edge.To],
+			Value:  edgeTypeAttraction[edge.Type],
+		})
 		s.modelToLayoutEdgeLookup[edge.ID] = index + len(s.ledges)
 	}
 	s.lnodes = append(s.lnodes, newNodes...)
@@ -97,3 +97,46 @@ func TestForceSimulationLayouter_Reload(t *testing.T) {
 	assert.Equal(map[string]int{"2": 0, "1": 1}, l.simulationState.modelToLayoutNodeLookup)
 	assert.Equal(map[string]int{"55": 0}, l.simulationState.modelToLayoutEdgeLookup)
 }
+
+func TestForceSimulationLayouter_NodesInViewport(t *testing.T) {
+	l := NewForceSimulationLayouter()
+	l.simulationState.lnodes = []*layout.Node{
+		{Name: "1", Pos: vector.Vector{0, 0}}, {Name: "2", Pos: vector.Vector{10, 10}}, {Name: "3", Pos: vector.Vector{100, 100}},
+	}
+	l.simulationState.ledges = []*layout.Edge{{Source: 0, Target: 1}}
+	l.simulationState.modelToLayoutNodeLookup = map[string]int{"1": 0, "2": 1, "3": 2}
+	l.simulationState.modelToLayoutEdgeLookup = map[string]int{"4": 0}
+	l.simulationState.spatialIndex = newSpatialIndex(l.simulationState)
+	close(l.waitForInitialLayout) // assume initial layout is there
+	ctx := context.Background()
+	assert := assert.New(t)
+	assert.ElementsMatch([]string{"1", "2"}, l.NodesInViewport(ctx, layout.Rect{X: -1, Y: -1, Width: 20, Height: 20}, 1))
+	assert.ElementsMatch([]string{"1", "2", "3"}, l.NodesInViewport(ctx, layout.Rect{X: -1, Y: -1, Width: 200, Height: 200}, 1))
+	assert.ElementsMatch([]string{"3"}, l.NodesInViewport(ctx, layout.Rect{X: 50, Y: 50, Width: 100, Height: 100}, 1))
+	assert.ElementsMatch([]string{"1", "2"}, l.NodesInViewport(ctx, layout.Rect{X: -1, Y: -1, Width: 200, Height: 200}, 0.5), "node without edges hidden")
+}
+
+func TestMinDegreeForZoom(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(0, minDegreeForZoom(2))
+	assert.Equal(0, minDegreeForZoom(1))
+	assert.Equal(1, minDegreeForZoom(0.5))
+	assert.Equal(2, minDegreeForZoom(0.25))
+	assert.Equal(0, minDegreeForZoom(0))
+}
+
+func TestAppendNodesAndEdges_edgeTypeAttraction(t *testing.T) {
+	assert := assert.New(t)
+	s := simulationState{modelToLayoutNodeLookup: map[string]int{}, modelToLayoutEdgeLookup: map[string]int{}}
+	_, edges := appendNodesAndEdges(&s,
+		[]*model.Node{{ID: "1"}, {ID: "2"}},
+		[]*model.Edge{
+			{ID: "3", From: "1", To: "2", Type: model.EdgeTypePrerequisite},
+			{ID: "4", From: "2", To: "1", Type: model.EdgeTypeRelated},
+		},
+	)
+	assert.Equal([]*layout.Edge{
+		{Source: 0, Target: 1, Value: 1.0},
+		{Source: 1, Target: 0, Value: 0.25},
+	}, edges)
+}
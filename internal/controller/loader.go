@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/suxatcode/learn-graph-poc-backend/graph/model"
+)
+
+// loaderWait is how long a loader collects keys before fetching them. Field
+// resolvers of the elements of a list run concurrently, so they all request
+// their key within this time.
+const loaderWait = time.Millisecond
+
+// loader batches the keys requested by concurrent calls to load into a
+// single call to fetch. Results are not cached, a later load fetches again.
+type loader[T any] struct {
+	fetch func(ctx context.Context, keys []string) (map[string]T, error)
+	mu    sync.Mutex
+	batch *loaderBatch[T]
+}
+
+type loaderBatch[T any] struct {
+	keys   []string
+	done   chan struct{}
+	values map[string]T
+	err    error
+}
+
+func newLoader[T any](fetch func(ctx context.Context, keys []string) (map[string]T, error)) *loader[T] {
+	return &loader[T]{fetch: fetch}
+}
+
+// load returns the value fetched for key, the zero value if fetch returned
+// none for it.
+func (l *loader[T]) load(ctx context.Context, key string) (T, error) {
+	l.mu.Lock()
+	batch := l.batch
+	if batch == nil {
+		batch = &loaderBatch[T]{done: make(chan struct{})}
+		l.batch = batch
+		time.AfterFunc(loaderWait, func() {
+			l.mu.Lock()
+			l.batch = nil
+			l.mu.Unlock()
+			batch.values, batch.err = l.fetch(ctx, batch.keys)
+			close(batch.done)
+		})
+	}
+	batch.keys = append(batch.keys, key)
+	l.mu.Unlock()
+	<-batch.done
+	return batch.values[key], batch.err
+}
+
+type contextKeyLoaders struct{}
+
+// loaders are the loaders of a single GraphQL operation.
+type loaders struct {
+	nodeResources *loader[[]*model.Resource]
+	myVotes       *loader[*float64]
+}
+
+// WithLoaders adds loaders to ctx, which batch the queries of field
+// resolvers within one GraphQL operation. Without them every field resolver
+// queries on its own.
+func (c *Controller) WithLoaders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKeyLoaders{}, &loaders{
+		nodeResources: newLoader(c.db.NodeResources),
+		myVotes:       newLoader(c.myVotes),
+	})
+}
+
+func loadersFromCtx(ctx context.Context) *loaders {
+	l, _ := ctx.Value(contextKeyLoaders{}).(*loaders)
+	return l
+}
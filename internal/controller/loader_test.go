@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/suxatcode/learn-graph-poc-backend/db"
+	"github.com/suxatcode/learn-graph-poc-backend/graph/model"
+)
+
+func TestLoader(t *testing.T) {
+	assert := assert.New(t)
+	mu := sync.Mutex{}
+	calls := [][]string{}
+	l := newLoader(func(ctx context.Context, keys []string) (map[string]int, error) {
+		mu.Lock()
+		calls = append(calls, keys)
+		mu.Unlock()
+		return map[string]int{"a": 1, "b": 2}, nil
+	})
+	results := make([]int, 3)
+	wg := sync.WaitGroup{}
+	for i, key := range []string{"a", "b", "c"} {
+		wg.Add(1)
+		go func(i int, key string) {
+			defer wg.Done()
+			v, err := l.load(context.Background(), key)
+			assert.NoError(err)
+			results[i] = v
+		}(i, key)
+	}
+	wg.Wait()
+	assert.Equal([]int{1, 2, 0}, results)
+	if assert.Len(calls, 1, "concurrent loads should be fetched together") {
+		assert.ElementsMatch([]string{"a", "b", "c"}, calls[0])
+	}
+
+	v, err := l.load(context.Background(), "a")
+	assert.NoError(err)
+	assert.Equal(1, v)
+	assert.Len(calls, 2, "later loads should fetch again")
+}
+
+func TestLoader_error(t *testing.T) {
+	l := newLoader(func(ctx context.Context, keys []string) (map[string]int, error) {
+		return nil, errors.New("AAA")
+	})
+	_, err := l.load(context.Background(), "a")
+	assert.EqualError(t, err, "AAA")
+}
+
+func TestController_NodeResources(t *testing.T) {
+	video := &model.Resource{ID: "1", Kind: model.ResourceKindVideo}
+	book := &model.Resource{ID: "2", Kind: model.ResourceKindBook}
+	kindVideo := model.ResourceKindVideo
+	for _, test := range []struct {
+		Name        string
+		WithLoaders bool
+		Kind        *model.ResourceKind
+		ExpectRes   map[string][]*model.Resource
+	}{
+		{
+			Name:      "without loaders",
+			ExpectRes: map[string][]*model.Resource{"1": {video, book}, "2": {}},
+		},
+		{
+			Name:        "with loaders",
+			WithLoaders: true,
+			ExpectRes:   map[string][]*model.Resource{"1": {video, book}, "2": {}},
+		},
+		{
+			Name:        "filter by kind",
+			WithLoaders: true,
+			Kind:        &kindVideo,
+			ExpectRes:   map[string][]*model.Resource{"1": {video}, "2": {}},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+			ctrl := gomock.NewController(t)
+			mock := db.NewMockDB(ctrl)
+			c := NewController(mock, nil)
+			ctx := context.Background()
+			if test.WithLoaders {
+				ctx = c.WithLoaders(ctx)
+				mock.EXPECT().NodeResources(ctx, gomock.InAnyOrder([]string{"1", "2"})).
+					Return(map[string][]*model.Resource{"1": {video, book}}, nil)
+			} else {
+				mock.EXPECT().NodeResources(ctx, []string{"1"}).Return(map[string][]*model.Resource{"1": {video, book}}, nil)
+				mock.EXPECT().NodeResources(ctx, []string{"2"}).Return(map[string][]*model.Resource{}, nil)
+			}
+			mu := sync.Mutex{}
+			res := map[string][]*model.Resource{}
+			wg := sync.WaitGroup{}
+			for _, id := range []string{"1", "2"} {
+				wg.Add(1)
+				go func(id string) {
+					defer wg.Done()
+					resources, err := c.NodeResources(ctx, id, test.Kind)
+					assert.NoError(err)
+					mu.Lock()
+					res[id] = resources
+					mu.Unlock()
+				}(id)
+			}
+			wg.Wait()
+			assert.Equal(test.ExpectRes, res)
+		})
+	}
+}
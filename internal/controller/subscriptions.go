@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/suxatcode/learn-graph-poc-backend/graph/model"
+)
+
+// subscriberBufferSize is the number of events buffered per subscriber,
+// slow subscribers miss events instead of blocking the publisher.
+const subscriberBufferSize = 16
+
+// broker fans out published events to all current subscribers.
+type broker[T any] struct {
+	mu          sync.Mutex
+	subscribers map[chan T]struct{}
+}
+
+func newBroker[T any]() *broker[T] {
+	return &broker[T]{subscribers: map[chan T]struct{}{}}
+}
+
+// subscribe returns a channel receiving all events published after this
+// call. The channel is closed once ctx is done.
+func (b *broker[T]) subscribe(ctx context.Context) <-chan T {
+	ch := make(chan T, subscriberBufferSize)
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subscribers, ch)
+		close(ch)
+		b.mu.Unlock()
+	}()
+	return ch
+}
+
+func (b *broker[T]) publish(event T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+func (c *Controller) SubscribeGraphChanged(ctx context.Context) (<-chan *model.GraphChange, error) {
+	log.Ctx(ctx).Debug().Msg("SubscribeGraphChanged()")
+	return c.graphEvents.subscribe(ctx), nil
+}
+
+// SubscribeNodeChanged sends the current state of node `id` after each change
+// to it. After deletion of the node nil is sent and the channel is closed.
+func (c *Controller) SubscribeNodeChanged(ctx context.Context, id string) (<-chan *model.Node, error) {
+	log.Ctx(ctx).Debug().Msgf("SubscribeNodeChanged(%s)", id)
+	if err := c.resolveNodeIDs(ctx, &id); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	changes := c.graphEvents.subscribe(ctx)
+	nodes := make(chan *model.Node, 1)
+	go func() {
+		defer close(nodes)
+		defer cancel()
+		for change := range changes {
+			if change.ID != id {
+				continue
+			}
+			switch change.Type {
+			case model.GraphChangeTypeNodeDeleted:
+				select {
+				case nodes <- nil:
+				case <-ctx.Done():
+				}
+				return
+			case model.GraphChangeTypeNodeEdited:
+				node, err := c.db.Node(ctx, id)
+				if err != nil {
+					log.Ctx(ctx).Error().Msgf("%v", err)
+					continue
+				}
+				select {
+				case nodes <- node:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return nodes, nil
+}
+
+func (c *Controller) SubscribeLayoutUpdated(ctx context.Context) (<-chan *model.Graph, error) {
+	log.Ctx(ctx).Debug().Msg("SubscribeLayoutUpdated()")
+	return c.layoutEvents.subscribe(ctx), nil
+}
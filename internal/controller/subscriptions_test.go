@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/suxatcode/learn-graph-poc-backend/db"
+	"github.com/suxatcode/learn-graph-poc-backend/graph/model"
+	"github.com/suxatcode/learn-graph-poc-backend/layout"
+)
+
+func receiveWithTimeout[T any](t *testing.T, ch <-chan T) (T, bool) {
+	select {
+	case v, ok := <-ch:
+		return v, ok
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for event")
+	}
+	var zero T
+	return zero, false
+}
+
+func TestBroker(t *testing.T) {
+	assert := assert.New(t)
+	b := newBroker[int]()
+	ctx, cancel := context.WithCancel(context.Background())
+	ch1, ch2 := b.subscribe(ctx), b.subscribe(context.Background())
+	b.publish(1)
+	v, ok := receiveWithTimeout(t, ch1)
+	assert.True(ok)
+	assert.Equal(1, v)
+	v, ok = receiveWithTimeout(t, ch2)
+	assert.True(ok)
+	assert.Equal(1, v)
+	cancel()
+	_, ok = receiveWithTimeout(t, ch1)
+	assert.False(ok, "channel should be closed after context is done")
+	// publishing must never block, even if nobody reads
+	for i := 0; i < subscriberBufferSize*2; i++ {
+		b.publish(i)
+	}
+}
+
+func TestController_SubscribeGraphChanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	c := NewController(db.NewMockDB(ctrl), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes, err := c.SubscribeGraphChanged(ctx)
+	assert := assert.New(t)
+	assert.NoError(err)
+	c.graphChanged(model.GraphChangeTypeEdgeCreated, "7")
+	change, ok := receiveWithTimeout(t, changes)
+	assert.True(ok)
+	assert.Equal(model.GraphChangeTypeEdgeCreated, change.Type)
+	assert.Equal("7", change.ID)
+}
+
+func TestController_SubscribeNodeChanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := db.NewMockDB(ctrl)
+	expectNoRedirects(mockDB)
+	c := NewController(mockDB, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mockDB.EXPECT().Node(gomock.Any(), "1").Return(&model.Node{ID: "1", Description: "new"}, nil)
+	nodes, err := c.SubscribeNodeChanged(ctx, "1")
+	assert := assert.New(t)
+	assert.NoError(err)
+	c.graphChanged(model.GraphChangeTypeNodeEdited, "2") // other node, ignored
+	c.graphChanged(model.GraphChangeTypeNodeEdited, "1")
+	node, ok := receiveWithTimeout(t, nodes)
+	assert.True(ok)
+	assert.Equal(&model.Node{ID: "1", Description: "new"}, node)
+	c.graphChanged(model.GraphChangeTypeNodeDeleted, "1")
+	node, ok = receiveWithTimeout(t, nodes)
+	assert.True(ok)
+	assert.Nil(node)
+	_, ok = receiveWithTimeout(t, nodes)
+	assert.False(ok, "channel should be closed after deletion")
+}
+
+func TestController_SubscribeLayoutUpdated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := db.NewMockDB(ctrl)
+	l := NewMockLayouter(ctrl)
+	c := NewController(mockDB, l)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	graph := &model.Graph{Nodes: []*model.Node{{ID: "1"}}}
+	mockDB.EXPECT().Graph(gomock.Any(), gomock.Any()).Return(graph, nil)
+	l.EXPECT().Reload(gomock.Any(), graph).Return(layout.Stats{Iterations: 5})
+	graphs, err := c.SubscribeLayoutUpdated(ctx)
+	assert := assert.New(t)
+	assert.NoError(err)
+	go c.periodicGraphEmbeddingComputation(ctx, make(chan time.Time), time.Second)
+	g, ok := receiveWithTimeout(t, graphs)
+	assert.True(ok)
+	assert.Equal(graph, g)
+}
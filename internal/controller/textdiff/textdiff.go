@@ -0,0 +1,123 @@
+// Package textdiff computes word- and line-level differences between texts.
+package textdiff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type Operation int
+
+const (
+	Equal Operation = iota
+	Insert
+	Delete
+)
+
+// MaxTokens is the maximal number of words or lines of each text, since the
+// memory and time needed grow with the product of both numbers.
+const MaxTokens = 1000
+
+// ErrTooLarge is returned for texts with more than MaxTokens words or lines.
+var ErrTooLarge = errors.New("text too large to compute its difference")
+
+// Chunk is a piece of text, that is either equal in both texts, inserted
+// into the new text or deleted from the old text.
+type Chunk struct {
+	Operation Operation
+	Text      string
+}
+
+// Words returns the word-level difference from text a to text b.
+// Concatenating all Equal and Delete chunks yields a, concatenating all Equal
+// and Insert chunks yields b.
+func Words(a, b string) ([]Chunk, error) {
+	return diff(splitWords(a), splitWords(b))
+}
+
+// Lines returns the line-level difference from text a to text b, see Words.
+func Lines(a, b string) ([]Chunk, error) {
+	return diff(splitLines(a), splitLines(b))
+}
+
+// splitWords splits s into words and the whitespace between them.
+func splitWords(s string) []string {
+	tokens := []string{}
+	start, wasSpace := 0, false
+	for i, r := range s {
+		isSpace := unicode.IsSpace(r)
+		if i > 0 && isSpace != wasSpace {
+			tokens = append(tokens, s[start:i])
+			start = i
+		}
+		wasSpace = isSpace
+	}
+	if start < len(s) {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+// splitLines splits s into lines, keeping the line breaks.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diff computes the longest common subsequence of the tokens, and returns the
+// tokens not part of it as insertions or deletions.
+func diff(a, b []string) ([]Chunk, error) {
+	if len(a) > MaxTokens || len(b) > MaxTokens {
+		return nil, fmt.Errorf("%w: %d and %d tokens, at most %d are allowed", ErrTooLarge, len(a), len(b), MaxTokens)
+	}
+	// lcs[i][j] is the length of the LCS of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	chunks := []Chunk{}
+	add := func(op Operation, text string) {
+		if n := len(chunks); n > 0 && chunks[n-1].Operation == op {
+			chunks[n-1].Text += text
+			return
+		}
+		chunks = append(chunks, Chunk{Operation: op, Text: text})
+	}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			add(Equal, a[i])
+			i, j = i+1, j+1
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			add(Delete, a[i])
+			i++
+		default:
+			add(Insert, b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		add(Delete, a[i])
+	}
+	for ; j < len(b); j++ {
+		add(Insert, b[j])
+	}
+	return chunks, nil
+}
@@ -0,0 +1,94 @@
+package textdiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWords(t *testing.T) {
+	for _, test := range []struct {
+		Name string
+		A, B string
+		Exp  []Chunk
+	}{
+		{
+			Name: "equal",
+			A:    "a b",
+			B:    "a b",
+			Exp:  []Chunk{{Equal, "a b"}},
+		},
+		{
+			Name: "both empty",
+			Exp:  []Chunk{},
+		},
+		{
+			Name: "inserted from empty",
+			B:    "a b",
+			Exp:  []Chunk{{Insert, "a b"}},
+		},
+		{
+			Name: "word replaced",
+			A:    "the quick fox",
+			B:    "the slow fox",
+			Exp:  []Chunk{{Equal, "the "}, {Delete, "quick"}, {Insert, "slow"}, {Equal, " fox"}},
+		},
+		{
+			Name: "word appended",
+			A:    "learn go",
+			B:    "learn go fast",
+			Exp:  []Chunk{{Equal, "learn go"}, {Insert, " fast"}},
+		},
+		{
+			Name: "multi-byte runes",
+			A:    "über straße",
+			B:    "über gasse",
+			Exp:  []Chunk{{Equal, "über "}, {Delete, "straße"}, {Insert, "gasse"}},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			chunks, err := Words(test.A, test.B)
+			assert.NoError(t, err)
+			assert.Equal(t, test.Exp, chunks)
+		})
+	}
+}
+
+func TestLines(t *testing.T) {
+	for _, test := range []struct {
+		Name string
+		A, B string
+		Exp  []Chunk
+	}{
+		{
+			Name: "line inserted",
+			A:    "a\nc\n",
+			B:    "a\nb\nc\n",
+			Exp:  []Chunk{{Equal, "a\n"}, {Insert, "b\n"}, {Equal, "c\n"}},
+		},
+		{
+			Name: "line removed",
+			A:    "a\nb",
+			B:    "a\n",
+			Exp:  []Chunk{{Equal, "a\n"}, {Delete, "b"}},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			chunks, err := Lines(test.A, test.B)
+			assert.NoError(t, err)
+			assert.Equal(t, test.Exp, chunks)
+		})
+	}
+}
+
+func TestWords_tooLarge(t *testing.T) {
+	assert := assert.New(t)
+	large := strings.Repeat("a ", MaxTokens)
+	_, err := Words(large, "b")
+	assert.ErrorIs(err, ErrTooLarge)
+	_, err = Lines("a", strings.Repeat("b\n", MaxTokens+1))
+	assert.ErrorIs(err, ErrTooLarge)
+	_, err = Words(strings.Repeat("a ", MaxTokens/2), "b")
+	assert.NoError(err)
+}
@@ -0,0 +1,153 @@
+// Package langdetect guesses the language of short texts offline, by the
+// script of their letters and by common words of the latin script languages.
+package langdetect
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Result of checking a text against its declared language.
+type Result struct {
+	// Mismatch is set, if the text is clearly not in the declared language.
+	Mismatch bool
+	// Suggested is the detected language, empty if it could not be detected
+	// with confidence.
+	Suggested string
+}
+
+type script int
+
+const (
+	scriptUnknown script = iota
+	scriptLatin
+	scriptHan
+	scriptKana // japanese, usually mixed with han
+)
+
+// languageScripts maps the supported languages to the script they are written
+// in.
+var languageScripts = map[string]script{
+	"en": scriptLatin,
+	"de": scriptLatin,
+	"es": scriptLatin,
+	"fr": scriptLatin,
+	"it": scriptLatin,
+	"zh": scriptHan,
+	"ja": scriptKana,
+}
+
+// stopwords are frequent words, that identify a latin script language.
+var stopwords = map[string][]string{
+	"en": {"the", "and", "of", "to", "is", "are", "a", "an", "for", "with", "on", "that", "as", "by", "from", "it", "this", "be", "or", "how", "into", "what", "which", "can", "has", "have", "was", "not", "at"},
+	"de": {"der", "die", "das", "und", "ist", "sind", "ein", "eine", "einer", "nicht", "mit", "von", "zu", "den", "dem", "des", "im", "für", "auf", "sich", "auch", "wie", "oder", "wird", "werden"},
+	"es": {"el", "los", "las", "y", "que", "un", "una", "es", "son", "por", "con", "para", "del", "se", "al", "como", "más", "lo", "su", "o"},
+	"fr": {"le", "les", "des", "et", "est", "sont", "une", "du", "que", "pour", "dans", "avec", "sur", "au", "aux", "par", "ce", "pas", "qui", "ou"},
+	"it": {"il", "gli", "di", "e", "che", "è", "uno", "per", "con", "della", "delle", "non", "sono", "nel", "nella", "da", "si", "come", "o", "anche"},
+}
+
+// letters unique to a latin script language among the supported ones
+var uniqueLetters = map[rune]string{
+	'ß': "de", 'ä': "de", 'ö': "de", 'ü': "de",
+	'ñ': "es", '¿': "es", '¡': "es",
+	'ç': "fr", 'ê': "fr", 'œ': "fr",
+}
+
+// Check returns whether text is clearly not written in the declared language,
+// and the language it is written in, if that can be detected. Languages
+// without known script are never reported as mismatch.
+func Check(language, text string) Result {
+	expected, ok := languageScripts[language]
+	if !ok {
+		return Result{}
+	}
+	counts := countScripts(text)
+	detected := Detect(text)
+	switch expected {
+	case scriptHan, scriptKana:
+		// acronyms and names in latin letters are common in chinese and
+		// japanese texts, only text without any han or kana is a mismatch
+		if counts[scriptLatin] > 0 && counts[scriptHan]+counts[scriptKana] == 0 {
+			return Result{Mismatch: true, Suggested: detected}
+		}
+		if expected == scriptHan && counts[scriptKana] > 0 {
+			return Result{Mismatch: true, Suggested: detected}
+		}
+	case scriptLatin:
+		if counts[scriptHan]+counts[scriptKana] > counts[scriptLatin] {
+			return Result{Mismatch: true, Suggested: detected}
+		}
+		if detected != "" && detected != language {
+			return Result{Mismatch: true, Suggested: detected}
+		}
+	}
+	return Result{}
+}
+
+// Detect returns the language of text, or an empty string if it cannot be
+// detected with confidence.
+func Detect(text string) string {
+	counts := countScripts(text)
+	switch {
+	case counts[scriptKana] > 0:
+		return "ja"
+	case counts[scriptHan] > counts[scriptLatin]:
+		return "zh"
+	case counts[scriptLatin] > 0:
+		return detectLatin(text)
+	}
+	return ""
+}
+
+func countScripts(text string) map[script]int {
+	counts := map[script]int{}
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			counts[scriptKana]++
+		case unicode.Is(unicode.Han, r):
+			counts[scriptHan]++
+		case unicode.Is(unicode.Latin, r):
+			counts[scriptLatin]++
+		}
+	}
+	return counts
+}
+
+// detectLatin scores the latin script languages by their stopwords and unique
+// letters, the best language must score at least 2 and twice as high as any
+// other language.
+func detectLatin(text string) string {
+	scores := map[string]int{}
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '¿' && r != '¡'
+	})
+	for _, word := range words {
+		for lang, list := range stopwords {
+			for _, stopword := range list {
+				if word == stopword {
+					scores[lang]++
+				}
+			}
+		}
+		for _, r := range word {
+			if lang, ok := uniqueLetters[r]; ok {
+				scores[lang]++
+				break
+			}
+		}
+	}
+	best, bestScore, secondScore := "", 0, 0
+	for _, lang := range []string{"de", "en", "es", "fr", "it"} {
+		switch score := scores[lang]; {
+		case score > bestScore:
+			best, bestScore, secondScore = lang, score, bestScore
+		case score > secondScore:
+			secondScore = score
+		}
+	}
+	if bestScore < 2 || bestScore < 2*secondScore {
+		return ""
+	}
+	return best
+}
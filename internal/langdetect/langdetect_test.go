@@ -0,0 +1,53 @@
+package langdetect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	for _, test := range []struct {
+		Text string
+		Exp  string
+	}{
+		{Text: "光合作用", Exp: "zh"},
+		{Text: "DNA 复制的过程", Exp: "zh"},
+		{Text: "ひらがなとカタカナ", Exp: "ja"},
+		{Text: "光合成の仕組み", Exp: "ja"},
+		{Text: "How plants convert sunlight into sugar", Exp: "en"},
+		{Text: "Die Photosynthese ist ein Prozess der Pflanzen", Exp: "de"},
+		{Text: "Größenordnung und Maßeinheiten", Exp: "de"},
+		{Text: "La fotosíntesis es el proceso por el que las plantas", Exp: "es"},
+		{Text: "La photosynthèse est un processus des plantes", Exp: "fr"},
+		{Text: "Apple", Exp: ""},
+		{Text: "Linear Algebra", Exp: ""},
+		{Text: "", Exp: ""},
+		{Text: "1 + 1 = 2", Exp: ""},
+	} {
+		assert.Equal(t, test.Exp, Detect(test.Text), "text: %q", test.Text)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Language string
+		Text     string
+		Exp      Result
+	}{
+		{Name: "latin text labeled chinese", Language: "zh", Text: "The derivative of a function", Exp: Result{Mismatch: true, Suggested: "en"}},
+		{Name: "short latin text labeled chinese", Language: "zh", Text: "Apple", Exp: Result{Mismatch: true}},
+		{Name: "chinese with acronym", Language: "zh", Text: "DNA 复制", Exp: Result{}},
+		{Name: "japanese labeled chinese", Language: "zh", Text: "光合成の仕組み", Exp: Result{Mismatch: true, Suggested: "ja"}},
+		{Name: "chinese labeled english", Language: "en", Text: "光合作用", Exp: Result{Mismatch: true, Suggested: "zh"}},
+		{Name: "german labeled english", Language: "en", Text: "Die Ableitung einer Funktion ist nicht immer definiert", Exp: Result{Mismatch: true, Suggested: "de"}},
+		{Name: "english", Language: "en", Text: "The derivative of a function", Exp: Result{}},
+		{Name: "undetectable latin text", Language: "de", Text: "Apple", Exp: Result{}},
+		{Name: "unknown language", Language: "xx", Text: "光合作用", Exp: Result{}},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Exp, Check(test.Language, test.Text))
+		})
+	}
+}
@@ -11,6 +11,10 @@ type QuadTreeConfig struct {
 
 var QUADTREE_DEFAULT_CONFIG = QuadTreeConfig{CapacityOfEachBlock: 10}
 
+// quadTreeMaxDepth limits subdivision of the QuadTree, nodes inserted at this
+// depth are always kept in the leaf, even if its capacity is exceeded.
+const quadTreeMaxDepth = 32
+
 type QuadTree struct {
 	Center          vector.Vector
 	TotalMass       float64
@@ -37,6 +41,13 @@ func (r *Rect) Contains(pos vector.Vector) bool {
 	return contains
 }
 
+// Intersects returns true if r and other overlap, touching edges count as
+// overlap.
+func (r *Rect) Intersects(other Rect) bool {
+	return r.X <= other.X+other.Width && other.X <= r.X+r.Width &&
+		r.Y <= other.Y+other.Height && other.Y <= r.Y+r.Height
+}
+
 func NewQuadTree(config *QuadTreeConfig, forceSimulation *ForceSimulation, boundary Rect) *QuadTree {
 	qt := new(QuadTree)
 	qt.config = config
@@ -69,14 +80,13 @@ func (qt *QuadTree) Insert(node *Node) bool {
 }
 
 func (qt *QuadTree) insert(node *Node, depth int) bool {
-	// FIXME(skep): if more than qt.forceSimulation.config.CapacityOfEachBlock
-	// nodes are at the exact same location, then this is an inifite loop!
-	// -> should wiggle those nodes a bit to divide them into different regions!
+	// Note: if more than qt.config.CapacityOfEachBlock nodes are at the exact
+	// same location, subdivision stops at quadTreeMaxDepth.
 	if !qt.Region.Contains(node.Pos) {
 		return false
 	}
 
-	if len(qt.Nodes) < qt.config.CapacityOfEachBlock {
+	if len(qt.Nodes) < qt.config.CapacityOfEachBlock || depth >= quadTreeMaxDepth {
 		qt.Nodes = append(qt.Nodes, node)
 		return true
 	} else {
@@ -114,6 +124,29 @@ func (qt *QuadTree) subdivide(depth int) {
 	}
 }
 
+// Query returns all nodes inside of rect.
+func (qt *QuadTree) Query(rect Rect) []*Node {
+	return qt.query(rect, []*Node{})
+}
+
+func (qt *QuadTree) query(rect Rect, found []*Node) []*Node {
+	if !qt.Region.Intersects(rect) {
+		return found
+	}
+	if qt.Children[0] == nil {
+		for _, node := range qt.Nodes {
+			if rect.Contains(node.Pos) {
+				found = append(found, node)
+			}
+		}
+		return found
+	}
+	for _, child := range qt.Children {
+		found = child.query(rect, found)
+	}
+	return found
+}
+
 func (qt *QuadTree) CalculateMasses() {
 	if qt.Children[0] == nil {
 		// Leaf
@@ -86,3 +86,28 @@ func TestRect_Center(t *testing.T) {
 	r := Rect{X: 1, Y: 1, Width: 2, Height: 10}
 	assert.Equal(t, vector.Vector{2.0, 6.0}, r.Center())
 }
+
+func TestQuadTree_Query(t *testing.T) {
+	rect := Rect{X: 0.0, Y: 0.0, Width: 10.0, Height: 10.0}
+	qt := NewQuadTree(&QuadTreeConfig{CapacityOfEachBlock: 2}, nil, rect)
+	a, b, c, d := &Node{Name: "A", Pos: vector.Vector{1, 1}}, &Node{Name: "B", Pos: vector.Vector{2, 2}},
+		&Node{Name: "C", Pos: vector.Vector{3, 3}}, &Node{Name: "D", Pos: vector.Vector{8, 8}}
+	for _, n := range []*Node{a, b, c, d} {
+		qt.Insert(n)
+	}
+	assert := assert.New(t)
+	assert.ElementsMatch([]*Node{a, b, c, d}, qt.Query(rect))
+	assert.ElementsMatch([]*Node{b, c}, qt.Query(Rect{X: 1.5, Y: 1.5, Width: 2, Height: 2}))
+	assert.ElementsMatch([]*Node{d}, qt.Query(Rect{X: 5, Y: 5, Width: 10, Height: 10}))
+	assert.Empty(qt.Query(Rect{X: 20, Y: 20, Width: 1, Height: 1}))
+}
+
+func TestQuadTree_InsertSamePosition(t *testing.T) {
+	rect := Rect{X: 0.0, Y: 0.0, Width: 10.0, Height: 10.0}
+	qt := NewQuadTree(&QuadTreeConfig{CapacityOfEachBlock: 2}, nil, rect)
+	assert := assert.New(t)
+	for i := 0; i < 5; i++ {
+		assert.True(qt.Insert(&Node{Pos: vector.Vector{1, 1}}))
+	}
+	assert.Len(qt.Query(rect), 5)
+}
@@ -3,14 +3,21 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/rs/zerolog/log"
 )
 
 const (
-	// TODO(maybe): could just use 'Accept-Language' header, with example content [en-US,en;q=0.9]
-	httpHeaderLanguage = "Language"
-	contextLanguage    = "Language"
+	// takes precedence over the languages in the Accept-Language header
+	httpHeaderLanguage       = "Language"
+	httpHeaderAcceptLanguage = "Accept-Language"
+	contextLanguage          = "Language"
+	contextLanguages         = "Languages"
+	// last entry of every fallback chain
+	DefaultLanguage = "en"
 
 	httpHeaderAuthenticationToken = "Authentication"
 	contextAuthenticationToken    = "Authentication"
@@ -29,16 +36,109 @@ func addGlobalLoggerToReqCtx(next http.Handler) http.Handler {
 	})
 }
 
-// TODO(skep): should verify the input language to be one of {en, es, de, zh, ...}, since it's a public API now!
+// SupportedLanguages are the languages content may be requested in.
+var SupportedLanguages = []string{"de", "en", "es", "fr", "it", "ja", "zh"}
+
+// AddLanguageAndLogging resolves the fallback chain of languages from the
+// Language and Accept-Language headers, see LanguageFallbackChain.
 func AddLanguageAndLogging(next http.Handler) http.Handler {
-	return translateHTTPHeaderToContextValue(next, headerConfig{
-		Name:       "language",
-		HTTPHeader: httpHeaderLanguage,
-		ContextKey: contextLanguage,
-		LoggerKey:  "lang",
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tags := []string{}
+		if language := r.Header.Get(httpHeaderLanguage); language != "" {
+			tags = append(tags, language)
+		}
+		tags = append(tags, ParseAcceptLanguage(r.Header.Get(httpHeaderAcceptLanguage))...)
+		if len(tags) == 0 {
+			log.Debug().Msgf("no language HTTP header (keys='%s','%s') found in request: %v", httpHeaderLanguage, httpHeaderAcceptLanguage, r.Header)
+			next.ServeHTTP(w, r)
+			return
+		}
+		languages := LanguageFallbackChain(tags)
+		ctx := context.WithValue(r.Context(), contextLanguage, languages[0])
+		ctx = context.WithValue(ctx, contextLanguages, languages)
+		logger := log.Ctx(r.Context()).With().Str("lang", languages[0]).Logger()
+		next.ServeHTTP(w, r.WithContext(logger.WithContext(ctx)))
 	})
 }
 
+// ParseAcceptLanguage returns the language tags of an Accept-Language header
+// ordered by descending quality, e.g. "en;q=0.8, de-AT" yields [de-AT en].
+// Tags with quality 0 and malformed entries are dropped.
+func ParseAcceptLanguage(header string) []string {
+	type weightedTag struct {
+		tag     string
+		quality float64
+	}
+	weighted := []weightedTag{}
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+			q, err := strconv.ParseFloat(param[len("q="):], 64)
+			if err != nil || q < 0 || q > 1 {
+				quality = 0
+				break
+			}
+			quality = q
+		}
+		if quality == 0 {
+			continue
+		}
+		weighted = append(weighted, weightedTag{tag: tag, quality: quality})
+	}
+	sort.SliceStable(weighted, func(i, j int) bool { return weighted[i].quality > weighted[j].quality })
+	tags := make([]string, 0, len(weighted))
+	for _, w := range weighted {
+		tags = append(tags, w.tag)
+	}
+	return tags
+}
+
+func isSupportedLanguage(lang string) bool {
+	for _, supported := range SupportedLanguages {
+		if lang == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// LanguageFallbackChain resolves language tags in order of preference to the
+// chain of languages content is looked up in, e.g. [de-AT] yields
+// [de-AT de en]. Tags whose primary language is not supported are dropped,
+// the DefaultLanguage always comes last.
+func LanguageFallbackChain(tags []string) []string {
+	chain := []string{}
+	seen := map[string]bool{}
+	add := func(lang string) {
+		if !seen[lang] {
+			seen[lang] = true
+			chain = append(chain, lang)
+		}
+	}
+	for _, tag := range tags {
+		primary, region, hasRegion := strings.Cut(tag, "-")
+		primary = strings.ToLower(primary)
+		if !isSupportedLanguage(primary) {
+			continue
+		}
+		if hasRegion && region != "" {
+			add(primary + "-" + strings.ToUpper(region))
+		}
+		add(primary)
+	}
+	add(DefaultLanguage)
+	return chain
+}
+
 func AddAuthentication(next http.Handler) http.Handler {
 	return translateHTTPHeaderToContextValue(next, headerConfig{
 		Name:         "authentication",
@@ -73,11 +173,29 @@ func CtxGetLanguage(ctx context.Context) string {
 	return ctxGetStringValueOrEmptyString(ctx, contextLanguage)
 }
 
+// CtxGetLanguages returns the fallback chain of languages of the request,
+// starting with CtxGetLanguage.
+func CtxGetLanguages(ctx context.Context) []string {
+	if languages, ok := ctx.Value(contextLanguages).([]string); ok {
+		return languages
+	}
+	if lang := CtxGetLanguage(ctx); lang != "" {
+		return []string{lang}
+	}
+	return nil
+}
+
 // testing purposes only
 func TestingCtxNewWithLanguage(ctx context.Context, lang string) context.Context {
 	return context.WithValue(ctx, contextLanguage, lang)
 }
 
+// testing purposes only
+func TestingCtxNewWithLanguages(ctx context.Context, languages ...string) context.Context {
+	ctx = context.WithValue(ctx, contextLanguage, languages[0])
+	return context.WithValue(ctx, contextLanguages, languages)
+}
+
 // testing purposes only
 func TestingCtxNewWithAuthentication(ctx context.Context, token string) context.Context {
 	return context.WithValue(ctx, contextAuthenticationToken, token)
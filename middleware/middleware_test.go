@@ -27,6 +27,90 @@ func TestAddLanguageMiddleware(t *testing.T) {
 	assert.True(t, called, "middleware handler must call next handler")
 }
 
+func TestAddLanguageMiddleware_AcceptLanguage(t *testing.T) {
+	for _, test := range []struct {
+		Name           string
+		Headers        map[string]string
+		ExpLanguage    string
+		ExpLanguages   []string
+		ExpNoLanguages bool
+	}{
+		{
+			Name:         "accept-language with region",
+			Headers:      map[string]string{"Accept-Language": "de-AT,de;q=0.9,en;q=0.8"},
+			ExpLanguage:  "de-AT",
+			ExpLanguages: []string{"de-AT", "de", "en"},
+		},
+		{
+			Name:         "language header takes precedence",
+			Headers:      map[string]string{"Language": "zh", "Accept-Language": "de"},
+			ExpLanguage:  "zh",
+			ExpLanguages: []string{"zh", "de", "en"},
+		},
+		{
+			Name:         "unsupported languages are dropped",
+			Headers:      map[string]string{"Language": "'; DROP TABLE nodes", "Accept-Language": "xx, fr;q=0.5"},
+			ExpLanguage:  "fr",
+			ExpLanguages: []string{"fr", "en"},
+		},
+		{
+			Name:         "only unsupported languages",
+			Headers:      map[string]string{"Accept-Language": "xx"},
+			ExpLanguage:  "en",
+			ExpLanguages: []string{"en"},
+		},
+		{
+			Name:           "no language headers",
+			Headers:        map[string]string{},
+			ExpNoLanguages: true,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			called := false
+			next := http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					called = true
+					if test.ExpNoLanguages {
+						assert.Equal(t, "", CtxGetLanguage(r.Context()))
+						assert.Nil(t, CtxGetLanguages(r.Context()))
+						return
+					}
+					assert.Equal(t, test.ExpLanguage, CtxGetLanguage(r.Context()))
+					assert.Equal(t, test.ExpLanguages, CtxGetLanguages(r.Context()))
+				},
+			)
+			req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "idk", nil)
+			for key, value := range test.Headers {
+				req.Header.Add(key, value)
+			}
+			AddLanguageAndLogging(next).ServeHTTP(nil, req)
+			assert.True(t, called, "middleware handler must call next handler")
+		})
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	for _, test := range []struct {
+		Header string
+		Exp    []string
+	}{
+		{Header: "", Exp: []string{}},
+		{Header: "en-US,en;q=0.9", Exp: []string{"en-US", "en"}},
+		{Header: "en;q=0.5, de-AT , fr;q=0.7", Exp: []string{"de-AT", "fr", "en"}},
+		{Header: "de;q=0, en;q=abc, *;q=0.1", Exp: []string{"*"}},
+		{Header: "zh;level=1;q=0.4,ja", Exp: []string{"ja", "zh"}},
+	} {
+		assert.Equal(t, test.Exp, ParseAcceptLanguage(test.Header), "header: %q", test.Header)
+	}
+}
+
+func TestLanguageFallbackChain(t *testing.T) {
+	assert.Equal(t, []string{"de-AT", "de", "en"}, LanguageFallbackChain([]string{"de-at"}))
+	assert.Equal(t, []string{"en-US", "en", "es"}, LanguageFallbackChain([]string{"en-US", "es", "en"}))
+	assert.Equal(t, []string{"en"}, LanguageFallbackChain([]string{"*", "xx-YY"}))
+	assert.Equal(t, []string{"en"}, LanguageFallbackChain(nil))
+}
+
 func TestCtxGetLanguage(t *testing.T) {
 	ctx := context.WithValue(context.Background(), "a", "c")
 	assert.Equal(t, "", CtxGetLanguage(ctx), "language key not found")
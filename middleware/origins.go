@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// AllowedOrigins are the origins browsers may open websockets from, besides
+// the origin of the API itself, e.g. the separately hosted frontend.
+type AllowedOrigins []string
+
+func (origins AllowedOrigins) allows(origin string) bool {
+	for _, allowed := range origins {
+		if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckOrigin reports whether the request may be served, i.e. whether it was
+// sent from the same origin, from an allowed origin, or without any origin by
+// a non-browser client. It is used for websocket upgrades, which are not
+// covered by CORS.
+func (origins AllowedOrigins) CheckOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	return origins.allows(origin)
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testOrigins = AllowedOrigins{"https://learngraph.org", "http://localhost:5173/"}
+
+func TestAllowedOrigins_CheckOrigin(t *testing.T) {
+	for _, test := range []struct {
+		Name   string
+		Origin string
+		Exp    bool
+	}{
+		{Name: "no origin, i.e. no browser", Origin: "", Exp: true},
+		{Name: "same origin", Origin: "https://api.learngraph.org", Exp: true},
+		{Name: "allowed origin", Origin: "https://learngraph.org", Exp: true},
+		{Name: "allowed origin with trailing slash in list", Origin: "http://localhost:5173", Exp: true},
+		{Name: "other origin", Origin: "https://evil.example", Exp: false},
+		{Name: "other port of allowed host", Origin: "https://learngraph.org:8443", Exp: false},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "https://api.learngraph.org/query", nil)
+			if test.Origin != "" {
+				req.Header.Set("Origin", test.Origin)
+			}
+			assert.Equal(t, test.Exp, testOrigins.CheckOrigin(req))
+		})
+	}
+}